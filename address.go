@@ -0,0 +1,45 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	i2c "github.com/d2r2/go-i2c"
+)
+
+// Sensor i2c-bus addresses, selected with ADDR pin
+// (see "Sensor I2C Address" in datasheet).
+const (
+	AddressDefault   = 0x44 // ADDR pin connected to VSS (logic low)
+	AddressAlternate = 0x45 // ADDR pin connected to VDD (logic high)
+)
+
+// NewSHT3XAt open i2c-bus connection to the sensor at address specified
+// (AddressDefault or AddressAlternate) on bus line busLine
+// (1 stands for /dev/i2c-1) and return new sensor object with it.
+// Connection must be closed by caller, once sensor is not needed anymore.
+func NewSHT3XAt(address uint8, busLine int) (*SHT3X, *i2c.I2C, error) {
+	bus, err := i2c.NewI2C(address, busLine)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewSHT3X(), bus, nil
+}
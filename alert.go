@@ -0,0 +1,310 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Temperature and relative humidity range,
+// which could be represented in sensor alert limits.
+const (
+	alertTemperatureMin = -45
+	alertTemperatureMax = 130
+	alertHumidityMin    = 0
+	alertHumidityMax    = 100
+)
+
+// AlertLimit keep temperature and relative humidity pair
+// which define single alert threshold.
+type AlertLimit struct {
+	Temperature      float32
+	RelativeHumidity float32
+}
+
+// AlertConfig keep all four alert thresholds of the sensor.
+// Equation must be respected: HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET.
+type AlertConfig struct {
+	HighSet   AlertLimit
+	HighClear AlertLimit
+	LowClear  AlertLimit
+	LowSet    AlertLimit
+}
+
+// AlertLimits is alias of AlertConfig, returned by ReadAllAlertLimits,
+// so limits read could be passed to SetAlertLimits or ApplyAlertConfig as is.
+type AlertLimits = AlertConfig
+
+// AlertOrderError returned, when pair of alert limits break equation
+// HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET.
+type AlertOrderError struct {
+	Higher      string  // Name of limit, which must be greater ("HIGH SET", ...)
+	Lower       string  // Name of limit, which must be less
+	Temperature bool    // Temperature is out of order, otherwise humidity
+	HigherValue float32 // Value of Higher limit
+	LowerValue  float32 // Value of Lower limit
+}
+
+// Error implement error interface.
+func (v *AlertOrderError) Error() string {
+	// Values might be ordered, but collapse to the same step,
+	// once quantized to resolution sensor keep limits with.
+	var suffix string
+	if v.HigherValue > v.LowerValue {
+		suffix = " at sensor resolution"
+	}
+	if v.Temperature {
+		return spew.Sprintf("Alert %s temperature %v*C must be greater than %s temperature %v*C%s",
+			v.Higher, v.HigherValue, v.Lower, v.LowerValue, suffix)
+	}
+	return spew.Sprintf("Alert %s humidity %v%% must be greater than %s humidity %v%%%s",
+		v.Higher, v.HigherValue, v.Lower, v.LowerValue, suffix)
+}
+
+// alertLimitItem keep single alert limit together
+// with the word it's stored in the sensor as.
+type alertLimitItem struct {
+	Name  string
+	Limit AlertLimit
+	Code  uint16
+}
+
+// checkAlertOrder verify equation HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET
+// on words stored in the sensor: 9-bit temperature (~0.34*C per step)
+// and 7-bit humidity (~0.78% per step) are compared, since it's what
+// sensor compare, rather than float values limits were given with.
+func checkAlertOrder(limits []alertLimitItem) error {
+	for i := 1; i < len(limits); i++ {
+		high, low := limits[i-1], limits[i]
+		if high.Code&0x01FF <= low.Code&0x01FF {
+			return &AlertOrderError{Higher: high.Name, Lower: low.Name,
+				Temperature: true, HigherValue: high.Limit.Temperature,
+				LowerValue: low.Limit.Temperature}
+		}
+		if high.Code>>9 <= low.Code>>9 {
+			return &AlertOrderError{Higher: high.Name, Lower: low.Name,
+				HigherValue: high.Limit.RelativeHumidity,
+				LowerValue:  low.Limit.RelativeHumidity}
+		}
+	}
+	return nil
+}
+
+// ValidateAlertConfig verify that alert configuration could be written to the sensor:
+// each limit is within representable range and equation
+// HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET is respected
+// both for temperature and humidity, once limits are quantized
+// to resolution sensor keep them with. No communication with sensor
+// happens here. Returned error describe first violated constraint;
+// ordering violation is reported with *AlertOrderError.
+// Calibration is not taken into account, since there is no sensor here:
+// ApplyAlertConfig and SetAlertLimits validate with offsets
+// defined by SetCalibration.
+func ValidateAlertConfig(config AlertConfig) error {
+	v := &SHT3X{formula: FormulaDatasheet}
+	return v.validateAlertConfig(config)
+}
+
+// validateAlertConfig is ValidateAlertConfig, which subtract calibration
+// offsets and quantize limits exactly as writeAlertData does.
+func (v *SHT3X) validateAlertConfig(config AlertConfig) error {
+	tempOffset, rhOffset := v.GetCalibration()
+	limits := []alertLimitItem{
+		{Name: "HIGH SET", Limit: config.HighSet},
+		{Name: "HIGH CLEAR", Limit: config.HighClear},
+		{Name: "LOW CLEAR", Limit: config.LowClear},
+		{Name: "LOW SET", Limit: config.LowSet},
+	}
+	for i, item := range limits {
+		temp := item.Limit.Temperature - tempOffset
+		rh := item.Limit.RelativeHumidity - rhOffset
+		if temp < alertTemperatureMin || temp > alertTemperatureMax {
+			return errors.New(spew.Sprintf(
+				"Alert %s temperature %v*C is out of range [%v..%v]*C",
+				item.Name, item.Limit.Temperature, alertTemperatureMin+tempOffset,
+				alertTemperatureMax+tempOffset))
+		}
+		if rh < alertHumidityMin || rh > alertHumidityMax {
+			return errors.New(spew.Sprintf(
+				"Alert %s humidity %v%% is out of range [%v..%v]%%",
+				item.Name, item.Limit.RelativeHumidity, alertHumidityMin+rhOffset,
+				alertHumidityMax+rhOffset))
+		}
+		limits[i].Code = v.encodeAlertLimit(temp, rh)
+	}
+	return checkAlertOrder(limits)
+}
+
+// readAlertConfig read all four alert limits from the sensor.
+// If calibrated is false, calibration offsets are not applied.
+func (v *SHT3X) readAlertConfig(i2c I2CBus, calibrated bool) (AlertConfig, error) {
+	var config AlertConfig
+	limits := []struct {
+		Cmd   []byte
+		Limit *AlertLimit
+	}{
+		{CMD_ALERT_READ_HIGH_SET, &config.HighSet},
+		{CMD_ALERT_READ_HIGH_CLEAR, &config.HighClear},
+		{CMD_ALERT_READ_LOW_CLEAR, &config.LowClear},
+		{CMD_ALERT_READ_LOW_SET, &config.LowSet},
+	}
+	for _, item := range limits {
+		temp, rh, err := v.readAlertData(i2c, item.Cmd, calibrated)
+		if err != nil {
+			return AlertConfig{}, err
+		}
+		*item.Limit = AlertLimit{Temperature: temp, RelativeHumidity: rh}
+	}
+	return config, nil
+}
+
+// ReadAllAlertLimits read all four alert limits from the sensor at once,
+// instead of calling ReadAlertHighSet, ReadAlertHighClear, ReadAlertLowClear
+// and ReadAlertLowSet one by one. CRC of each limit is verified.
+func (v *SHT3X) ReadAllAlertLimits(i2c I2CBus) (AlertLimits, error) {
+	lg.Debug("Getting all alert limits...")
+	// Reroute call
+	return v.readAlertConfig(i2c, true)
+}
+
+// CheckAlertLimitsValid read alert limits currently stored in the sensor
+// and verify them the way ValidateAlertConfig does. Non-nil error means either
+// communication failure, or that sensor was left in non-functional alert state,
+// where equation HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET is broken.
+func (v *SHT3X) CheckAlertLimitsValid(i2c I2CBus) error {
+	lg.Debug("Checking alert limits...")
+	limits := []alertLimitItem{
+		{Name: "HIGH SET"},
+		{Name: "HIGH CLEAR"},
+		{Name: "LOW CLEAR"},
+		{Name: "LOW SET"},
+	}
+	cmds := [][]byte{CMD_ALERT_READ_HIGH_SET, CMD_ALERT_READ_HIGH_CLEAR,
+		CMD_ALERT_READ_LOW_CLEAR, CMD_ALERT_READ_LOW_SET}
+	for i, cmd := range cmds {
+		u, err := v.readAlertRaw(i2c, cmd)
+		if err != nil {
+			return err
+		}
+		temp, rh := v.decodeAlertLimit(u, true)
+		limits[i].Limit = AlertLimit{Temperature: temp, RelativeHumidity: rh}
+		limits[i].Code = u
+	}
+	// Compare words as stored, since limits decoded and encoded back
+	// might land on neighbour step
+	return checkAlertOrder(limits)
+}
+
+// writeAlertConfig write all four alert limits to the sensor.
+// Limits are written in order, which keep intermediate states valid,
+// if new limits are wider than old ones. If calibrated is false,
+// limits are written as is, without calibration offsets subtracted.
+func (v *SHT3X) writeAlertConfig(i2c I2CBus, config AlertConfig, calibrated bool) error {
+	limits := []struct {
+		Cmd   []byte
+		Limit AlertLimit
+	}{
+		{CMD_ALERT_WRITE_HIGH_SET, config.HighSet},
+		{CMD_ALERT_WRITE_HIGH_CLEAR, config.HighClear},
+		{CMD_ALERT_WRITE_LOW_SET, config.LowSet},
+		{CMD_ALERT_WRITE_LOW_CLEAR, config.LowClear},
+	}
+	for _, item := range limits {
+		write := v.writeAlertData
+		if !calibrated {
+			write = v.writeAlertDataUncalibrated
+		}
+		err := write(i2c, item.Cmd, item.Limit.Temperature,
+			item.Limit.RelativeHumidity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyAlertConfig validate alert configuration with ValidateAlertConfig,
+// taking calibration offsets into account, and write all four limits
+// to the sensor. Configuration is remembered,
+// to be restored after sensor reset (see SetRestoreAlertsOnReset).
+func (v *SHT3X) ApplyAlertConfig(i2c I2CBus, config AlertConfig) error {
+	lg.Debug("Applying alert configuration...")
+	err := v.validateAlertConfig(config)
+	if err != nil {
+		return err
+	}
+	err = v.writeAlertConfig(i2c, config, true)
+	if err != nil {
+		return err
+	}
+	v.mu.Lock()
+	v.alertConfig = &config
+	v.mu.Unlock()
+	return nil
+}
+
+// SetRestoreAlertsOnReset enable or disable automatic restore of alert
+// configuration, last applied with ApplyAlertConfig, once RESET_DETECTED
+// flag is found in status register (reset wipe alert limits back to defaults,
+// for instance on power glitch). Then status register is cleared,
+// to detect next reset. Detection happens on status register read,
+// so read it periodically (for instance, with WatchStatus) in long-running programs.
+func (v *SHT3X) SetRestoreAlertsOnReset(restore bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.restoreAlerts = restore
+}
+
+// restoreAlertConfig re-apply last alert configuration after sensor reset,
+// if enabled.
+func (v *SHT3X) restoreAlertConfig(i2c I2CBus) error {
+	v.mu.Lock()
+	config := v.alertConfig
+	restore := v.restoreAlerts
+	v.mu.Unlock()
+	if !restore || config == nil {
+		return nil
+	}
+	lg.Info("Sensor reset detected, restoring alert configuration")
+	err := v.writeAlertConfig(i2c, *config, true)
+	if err != nil {
+		return err
+	}
+	return v.ClearStatusReg(i2c)
+}
+
+// SetAlertLimits write all four alert limits to the sensor, verifying
+// in software first, that limits are in range and equation
+// HIGH SET > HIGH CLEAR > LOW CLEAR > LOW SET is respected, so no i2c
+// write happens with invalid limits. Ordering violation is reported
+// with *AlertOrderError, which identify pair of limits out of order.
+// It's the same as ApplyAlertConfig.
+func (v *SHT3X) SetAlertLimits(i2c I2CBus, highSet, highClear, lowClear,
+	lowSet AlertLimit) error {
+
+	config := AlertConfig{HighSet: highSet, HighClear: highClear,
+		LowClear: lowClear, LowSet: lowSet}
+	// Reroute call
+	return v.ApplyAlertConfig(i2c, config)
+}
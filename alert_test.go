@@ -0,0 +1,131 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"errors"
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestWriteAlertOutOfRange(t *testing.T) {
+	tests := []struct {
+		name                 string
+		tempOffset, rhOffset float32
+		temp, rh             float32
+	}{
+		{"temperature below minimum", 0, 0, -45.01, 50},
+		{"temperature above maximum", 0, 0, 130.01, 50},
+		{"extreme cold", 0, 0, -273.15, 50},
+		{"extreme heat", 0, 0, 1000, 50},
+		{"humidity below minimum", 0, 0, 25, -0.01},
+		{"humidity above maximum", 0, 0, 25, 100.01},
+		// values in range, but out of range once offset is subtracted
+		{"temperature above maximum after offset", -1, 0, 130, 50},
+		{"temperature below minimum after offset", 1, 0, -45, 50},
+		{"humidity below minimum after offset", 0, 5, 25, 2},
+		{"humidity above maximum after offset", 0, -5, 25, 98},
+	}
+	for _, test := range tests {
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		sensor := sht3x.NewSHT3X()
+		sensor.SetCalibration(test.tempOffset, test.rhOffset)
+		err := sensor.WriteAlertHighSet(bus, test.temp, test.rh)
+		if err != sht3x.ErrValueOutOfRange {
+			t.Errorf("%s: got error %v, want ErrValueOutOfRange", test.name, err)
+		}
+		if len(bus.Writes) != 0 {
+			t.Errorf("%s: got %d bus writes, want none", test.name, len(bus.Writes))
+		}
+	}
+}
+
+func TestWriteAlertInRange(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	sensor := sht3x.NewSHT3X()
+	// 100% with 5% offset is written as 95%
+	sensor.SetCalibration(0, 5)
+	err := sensor.WriteAlertHighSet(bus, 60, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bus.Writes) != 1 || len(bus.Writes[0]) != 5 {
+		t.Fatalf("got bus writes %X, want single command with data and CRC", bus.Writes)
+	}
+}
+
+func TestApplyAlertConfigQuantizedOrder(t *testing.T) {
+	lowClear := sht3x.AlertLimit{Temperature: 20, RelativeHumidity: 30}
+	lowSet := sht3x.AlertLimit{Temperature: 10, RelativeHumidity: 20}
+	tests := []struct {
+		name      string
+		rhOffset  float32
+		highSet   sht3x.AlertLimit
+		highClear sht3x.AlertLimit
+		valid     bool
+	}{
+		// 80% and 79.9% both are stored as humidity step 0x66
+		{"same humidity step", 0, sht3x.AlertLimit{Temperature: 60, RelativeHumidity: 80},
+			sht3x.AlertLimit{Temperature: 59.9, RelativeHumidity: 79.9}, false},
+		{"neighbour humidity steps", 0, sht3x.AlertLimit{Temperature: 60, RelativeHumidity: 80.5},
+			sht3x.AlertLimit{Temperature: 59.9, RelativeHumidity: 80}, true},
+		// 80.3% and 79.8% once offset is subtracted, both are stored as step 0x66
+		{"same humidity step after offset", 0.2,
+			sht3x.AlertLimit{Temperature: 60, RelativeHumidity: 80.5},
+			sht3x.AlertLimit{Temperature: 59.9, RelativeHumidity: 80}, false},
+	}
+	for _, test := range tests {
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		sensor := sht3x.NewSHT3X()
+		sensor.SetCalibration(0, test.rhOffset)
+		config := sht3x.AlertConfig{HighSet: test.highSet, HighClear: test.highClear,
+			LowClear: lowClear, LowSet: lowSet}
+		err := sensor.ApplyAlertConfig(bus, config)
+		if test.valid {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+			continue
+		}
+		var orderErr *sht3x.AlertOrderError
+		if !errors.As(err, &orderErr) {
+			t.Errorf("%s: got error %v, want AlertOrderError", test.name, err)
+			continue
+		}
+		if orderErr.Higher != "HIGH SET" || orderErr.Lower != "HIGH CLEAR" ||
+			orderErr.Temperature {
+			t.Errorf("%s: got %+v, want HIGH SET and HIGH CLEAR humidity", test.name, *orderErr)
+		}
+		if len(bus.Writes) != 0 {
+			t.Errorf("%s: got %d bus writes, want none", test.name, len(bus.Writes))
+		}
+	}
+	// The same collision is reported without sensor
+	err := sht3x.ValidateAlertConfig(sht3x.AlertConfig{HighSet: tests[0].highSet,
+		HighClear: tests[0].highClear, LowClear: lowClear, LowSet: lowSet})
+	var orderErr *sht3x.AlertOrderError
+	if !errors.As(err, &orderErr) {
+		t.Errorf("got error %v, want AlertOrderError", err)
+	}
+}
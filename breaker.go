@@ -0,0 +1,170 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState define circuit breaker state.
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota + 1 // Reads pass through
+	BreakerOpen                             // Reads fail fast
+	BreakerHalfOpen                         // Single trial read allowed
+)
+
+// String define stringer interface.
+func (v BreakerState) String() string {
+	switch v {
+	case BreakerClosed:
+		return "Breaker Closed"
+	case BreakerOpen:
+		return "Breaker Open"
+	case BreakerHalfOpen:
+		return "Breaker Half-Open"
+	default:
+		return "<unknown>"
+	}
+}
+
+// ErrBreakerOpen returned by CircuitBreaker, while reads are suspended.
+var ErrBreakerOpen = errors.New("Circuit breaker is open, sensor reads are suspended")
+
+// CircuitBreaker wrap sensor to stop hammering the bus, once sensor
+// fails most of reads. When failure ratio among last Window reads reach
+// FailureRatio, breaker "opens" and fail all reads with ErrBreakerOpen
+// during Cooldown period. Then single trial read is allowed ("half-open"):
+// success close breaker, failure open it again for next cooldown period.
+// Breaker is safe for concurrent use: only one trial read is admitted
+// in half-open state, others fail with ErrBreakerOpen. Exported fields
+// must not be changed once breaker is shared between goroutines.
+type CircuitBreaker struct {
+	sensor       *SHT3X
+	FailureRatio float64
+	Window       int
+	Cooldown     time.Duration
+	// mu guard fields below
+	mu       sync.Mutex
+	state    BreakerState
+	trial    bool // Trial read is in progress in half-open state
+	results  []bool
+	openedAt time.Time
+}
+
+// NewCircuitBreaker return new circuit breaker in closed state.
+func NewCircuitBreaker(sensor *SHT3X, failureRatio float64, window int,
+	cooldown time.Duration) *CircuitBreaker {
+
+	v := &CircuitBreaker{sensor: sensor, FailureRatio: failureRatio,
+		Window: window, Cooldown: cooldown, state: BreakerClosed}
+	return v
+}
+
+// State return current breaker state.
+func (v *CircuitBreaker) State() BreakerState {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.stateLocked()
+}
+
+// stateLocked is State for callers, which already hold mu.
+func (v *CircuitBreaker) stateLocked() BreakerState {
+	if v.state == BreakerOpen && v.sensor.now().Sub(v.openedAt) >= v.Cooldown {
+		return BreakerHalfOpen
+	}
+	return v.state
+}
+
+// Do execute f, if breaker state allows it, and account result.
+func (v *CircuitBreaker) Do(f func() error) error {
+	v.mu.Lock()
+	v.state = v.stateLocked()
+	if v.state == BreakerOpen || v.state == BreakerHalfOpen && v.trial {
+		v.mu.Unlock()
+		return ErrBreakerOpen
+	}
+	trial := v.state == BreakerHalfOpen
+	v.trial = trial
+	v.mu.Unlock()
+
+	err := f()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if trial {
+		v.trial = false
+		if err != nil {
+			lg.Debug("Trial read failed, circuit breaker open again")
+			v.open()
+		} else {
+			lg.Debug("Trial read succeeded, circuit breaker closed")
+			v.state = BreakerClosed
+			v.results = nil
+		}
+		return err
+	}
+	v.results = append(v.results, err != nil)
+	if len(v.results) > v.Window {
+		v.results = v.results[len(v.results)-v.Window:]
+	}
+	if v.state == BreakerClosed && len(v.results) >= v.Window {
+		failures := 0
+		for _, failed := range v.results {
+			if failed {
+				failures++
+			}
+		}
+		if float64(failures)/float64(len(v.results)) >= v.FailureRatio {
+			lg.Warnf("%d of %d last reads failed, circuit breaker open", failures, len(v.results))
+			v.open()
+		}
+	}
+	return err
+}
+
+// open switch breaker to open state.
+// Must be called with mu locked.
+func (v *CircuitBreaker) open() {
+	v.state = BreakerOpen
+	v.openedAt = v.sensor.now()
+	v.results = nil
+}
+
+// ReadMeasurement make measurement in "single shot mode" via breaker.
+func (v *CircuitBreaker) ReadMeasurement(i2c I2CBus,
+	precision MeasureRepeatability) (Measurement, error) {
+
+	var m Measurement
+	err := v.Do(func() error {
+		var err error
+		m, err = v.sensor.ReadMeasurement(i2c, precision)
+		return err
+	})
+	if err != nil {
+		return Measurement{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,91 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sht3x "github.com/d2r2/go-sht3x"
+)
+
+func TestCircuitBreakerSingleTrial(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor := sht3x.NewSHT3X()
+	sensor.SetClock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	})
+	breaker := sht3x.NewCircuitBreaker(sensor, 0.5, 2, time.Minute)
+	failure := errors.New("read failed")
+	for i := 0; i < 2; i++ {
+		breaker.Do(func() error { return failure })
+	}
+	if state := breaker.State(); state != sht3x.BreakerOpen {
+		t.Fatalf("state = %v, want %v", state, sht3x.BreakerOpen)
+	}
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+
+	const goroutines = 8
+	var trials int32
+	release := make(chan struct{})
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			errs <- breaker.Do(func() error {
+				atomic.AddInt32(&trials, 1)
+				<-release
+				return nil
+			})
+		}()
+	}
+	// all calls but trial one must fail fast
+	for i := 0; i < goroutines-1; i++ {
+		select {
+		case err := <-errs:
+			if err != sht3x.ErrBreakerOpen {
+				t.Errorf("got error %v, want ErrBreakerOpen", err)
+			}
+		case <-time.After(5 * time.Second):
+			close(release)
+			t.Fatalf("%d trial reads admitted in half-open state, want 1",
+				atomic.LoadInt32(&trials))
+		}
+	}
+	close(release)
+	if err := <-errs; err != nil {
+		t.Errorf("trial read: %v", err)
+	}
+	if n := atomic.LoadInt32(&trials); n != 1 {
+		t.Errorf("%d trial reads admitted, want 1", n)
+	}
+	if state := breaker.State(); state != sht3x.BreakerClosed {
+		t.Errorf("state = %v, want %v", state, sht3x.BreakerClosed)
+	}
+}
@@ -0,0 +1,61 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestReadRawAndCalibrated(t *testing.T) {
+	tests := []struct {
+		tempOffset, rhOffset float32
+		calTemp, calRH       float32
+	}{
+		{0, 0, 25, 50},
+		{-1.5, 3, 23.5, 53},
+		// humidity is limited to 100% once offset applied
+		{2, 60, 27, 100},
+	}
+	for _, test := range tests {
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_HIGH, sht3xtest.Words(0x6666, 0x8000))
+		sensor := sht3x.NewSHT3X()
+		sensor.SetCalibration(test.tempOffset, test.rhOffset)
+		rawTemp, calTemp, rawRH, calRH, err := sensor.ReadRawAndCalibrated(bus,
+			sht3x.RepeatabilityHigh)
+		if err != nil {
+			t.Errorf("offsets %v, %v: %v", test.tempOffset, test.rhOffset, err)
+			continue
+		}
+		if rawTemp != 25 || rawRH != 50 {
+			t.Errorf("offsets %v, %v: raw %v*C, %v%%, want 25*C, 50%%",
+				test.tempOffset, test.rhOffset, rawTemp, rawRH)
+		}
+		if calTemp != test.calTemp || calRH != test.calRH {
+			t.Errorf("offsets %v, %v: calibrated %v*C, %v%%, want %v*C, %v%%",
+				test.tempOffset, test.rhOffset, calTemp, calRH, test.calTemp, test.calRH)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Pause after raw command, since no timing is known for it.
+// The same pause is used after commands, for which specification
+// doesn't define execution time.
+const rawCommandPause = time.Millisecond
+
+// SendCommand write raw command to the sensor, which is an escape hatch
+// for commands not wrapped by the package (for instance, new firmware
+// features). Command is remembered as the last one sent, the same way
+// as for wrapped commands, so Fetch... methods correctly report,
+// that "periodic data acquisition mode" is not active anymore,
+// unless command restart it. Known periodic measurement commands
+// (CMD_PERIOD_MEASURE_...) and CMD_ART are recognized, so Fetch... methods
+// work after them, as after StartPeriodicTemperatureAndHumidityMeasure
+// and EnableART. Cached status register is invalidated,
+// since command might change it.
+func (v *SHT3X) SendCommand(i2c I2CBus, cmd []byte) error {
+	lg.Debugf("Sending raw command 0x%X...", cmd)
+	err := v.initiateMeasure(i2c, cmd, rawCommandPause)
+	if err != nil {
+		return err
+	}
+	v.invalidateStatusReg()
+	if bytes.Equal(cmd, CMD_ART) {
+		v.mu.Lock()
+		v.lastPeriodic = Periodic4MPS
+		v.mu.Unlock()
+	} else if period, precision, ok := v.findPeriodicCommand(cmd); ok {
+		v.setPeriodic(period, precision)
+	}
+	return nil
+}
+
+// findPeriodicCommand return parameters of "periodic data acquisition mode"
+// started by command, or false, if command doesn't start it.
+func (v *SHT3X) findPeriodicCommand(cmd []byte) (PeriodicMeasure, MeasureRepeatability, bool) {
+	for period := PeriodicHalfMPS; period <= Periodic10MPS; period++ {
+		for precision := RepeatabilityLow; precision <= RepeatabilityHigh; precision++ {
+			if bytes.Equal(cmd, v.getPeriodicMeasurementCommand(period, precision)) {
+				return period, precision, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ReadCommandResult send raw command with SendCommand and read
+// blockCount 16-bit words in reply, verifying CRC of each one.
+// Negative blockCount is rejected before command is sent.
+func (v *SHT3X) ReadCommandResult(i2c I2CBus, cmd []byte,
+	blockCount int) ([]uint16, error) {
+
+	if blockCount < 0 {
+		return nil, errors.New(spew.Sprintf("Block count must not be negative, but %d is given",
+			blockCount))
+	}
+	err := v.SendCommand(i2c, cmd)
+	if err != nil {
+		return nil, err
+	}
+	data, err := v.readDataWithCRCCheck(i2c, blockCount)
+	if err != nil {
+		return nil, wrapBusError(i2c, cmd, err)
+	}
+	return data, nil
+}
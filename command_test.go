@@ -0,0 +1,58 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestReadCommandResultNegativeBlockCount(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	sensor := sht3x.NewSHT3X()
+	_, err := sensor.ReadCommandResult(bus, sht3x.CMD_READ_SERIAL, -1)
+	if err == nil {
+		t.Error("negative block count accepted")
+	}
+	if len(bus.Writes) != 0 {
+		t.Errorf("got bus writes %X, want none", bus.Writes)
+	}
+}
+
+func TestSendCommandPeriodicStart(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_PERIOD_FETCH, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	err := sensor.SendCommand(bus, sht3x.CMD_PERIOD_MEASURE_1MPS_HIGH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	temp, rh, err := sensor.FetchTemperatureAndRelativeHumidity(bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temp != 25 || rh != 50 {
+		t.Errorf("got %v*C, %v%%, want 25*C, 50%%", temp, rh)
+	}
+}
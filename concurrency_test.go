@@ -0,0 +1,93 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+// Run with "go test -race" to verify sensor shared between
+// goroutines doesn't race on internal state.
+func TestConcurrentReads(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	bus.SetResponse(sht3x.CMD_READ_STATUS_REG, sht3xtest.Words(0x0000))
+	sensor := sht3x.NewSHT3X()
+
+	const goroutines, reads = 8, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines*reads)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < reads; j++ {
+				err := sensor.WithBusLock(func() error {
+					temp, rh, err := sensor.ReadTemperatureAndRelativeHumidity(bus,
+						sht3x.RepeatabilityLow)
+					if err != nil {
+						return err
+					}
+					if temp != 25 || rh != 50 {
+						t.Errorf("got %v*C, %v%%, want 25*C, 50%%", temp, rh)
+					}
+					_, err = sensor.ReadStatusRegFresh(bus)
+					return err
+				})
+				if err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	// settings and statistics accessed concurrently with reads
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < reads; j++ {
+			sensor.SetCalibration(0, 0)
+			sensor.SetHeaterSettleWindow(time.Second)
+			sensor.SetStuckDetection(0)
+			sensor.SetMeasurementTimeout(0)
+			sensor.SetConversionFormula(sht3x.FormulaDatasheet)
+			sensor.SetSuppressDuplicates(false)
+			_ = sensor.TimingStats()
+			_ = sensor.GetSuppressedDuplicates()
+			_, _ = sensor.TimeSinceReset()
+			_ = sensor.GetModel()
+		}
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if stats := sensor.TimingStats(); stats.Reads != 2*goroutines*reads {
+		t.Errorf("got %d reads in timing statistics, want %d", stats.Reads,
+			2*goroutines*reads)
+	}
+}
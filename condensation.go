@@ -0,0 +1,63 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+// CondensationDetector is a software detector of condensation risk
+// based on measured relative humidity. Condensation state is activated
+// when humidity rises to High threshold, and deactivated only when
+// humidity falls down to Low threshold. Gap between thresholds gives
+// hysteresis, which prevents state flapping near the single threshold.
+// Works independently of sensor hardware alerts.
+type CondensationDetector struct {
+	High   float32 // Relative humidity to activate condensation state
+	Low    float32 // Relative humidity to clear condensation state
+	active bool
+}
+
+// NewCondensationDetector return new detector with thresholds specified.
+// High threshold expected to be greater than low one.
+func NewCondensationDetector(high, low float32) *CondensationDetector {
+	v := &CondensationDetector{High: high, Low: low}
+	return v
+}
+
+// Update process next measurement and return event flag,
+// which is true when condensation state changed, and
+// current condensation state itself.
+func (v *CondensationDetector) Update(m Measurement) (event, active bool) {
+	prev := v.active
+	if v.active {
+		if m.RelativeHumidity <= v.Low {
+			v.active = false
+		}
+	} else {
+		if m.RelativeHumidity >= v.High {
+			v.active = true
+		}
+	}
+	return prev != v.active, v.active
+}
+
+// Active return current condensation state.
+func (v *CondensationDetector) Active() bool {
+	return v.active
+}
@@ -0,0 +1,116 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"math/big"
+	"testing"
+)
+
+// exactRound return raw*scale/0xFFFF-offset computed with rational numbers
+// and rounded to 2 decimal places half away from zero, which is reference
+// for conversion made in floating point.
+func exactRound(raw uint16, scale, offset int64) float32 {
+	r := big.NewRat(int64(raw)*scale, 0xFFFF)
+	r.Sub(r, big.NewRat(offset, 1))
+	r.Mul(r, big.NewRat(100, 1))
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+	// (2*|num| + den) / (2*den) round half away from zero
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	q := num.Div(num, new(big.Int).Mul(den, big.NewInt(2))).Int64()
+	if r.Sign() < 0 {
+		q = -q
+	}
+	return float32(float64(q) / 100)
+}
+
+func TestUncompTemperatureToCelsiusColdEnd(t *testing.T) {
+	v := NewSHT3X()
+	tests := []struct {
+		raw  uint16
+		temp float32
+	}{
+		{0x0000, -45},
+		{0x0001, -45},
+		{0x0040, -44.83},
+		{0x0080, -44.66},
+		{0x00FF, -44.32},
+		{0x0100, -44.32},
+	}
+	for _, test := range tests {
+		if temp := v.uncompTemperatureToCelsius(test.raw); temp != test.temp {
+			t.Errorf("raw 0x%04X: got %v*C, want %v*C", test.raw, temp, test.temp)
+		}
+	}
+	for raw := 0; raw <= 0x100; raw++ {
+		want := exactRound(uint16(raw), 175, 45)
+		if temp := v.uncompTemperatureToCelsius(uint16(raw)); temp != want {
+			t.Errorf("raw 0x%04X: got %v*C, want %v*C", raw, temp, want)
+		}
+	}
+}
+
+// Raw values, which were rounded to wrong side of boundary,
+// when conversion was made in float32.
+func TestConversionRoundingBoundaries(t *testing.T) {
+	v := NewSHT3X()
+	temps := []struct {
+		raw  uint16
+		temp float32
+	}{
+		{0x01C7, -43.79},
+		{0x0555, -41.36},
+		{0x316C, -11.21},
+		{0x34FA, -8.79},
+		{0x3888, -6.36},
+		{0xC05B, 86.5},
+		{0xC777, 91.36},
+		{0xD221, 98.64},
+		{0xD93D, 103.5},
+		{0xF38E, 121.5},
+		{0xFAAA, 126.36},
+	}
+	for _, test := range temps {
+		if temp := v.uncompTemperatureToCelsius(test.raw); temp != test.temp {
+			t.Errorf("raw 0x%04X: got %v*C, want %v*C", test.raw, temp, test.temp)
+		}
+	}
+	hums := []struct {
+		raw uint16
+		rh  float32
+	}{
+		{0x431C, 26.21},
+		{0x567D, 33.79},
+		{0x62EE, 38.64},
+		{0x69DE, 41.36},
+		{0x764F, 46.21},
+		{0x89B0, 53.79},
+		{0x9621, 58.64},
+		{0x9D11, 61.36},
+	}
+	for _, test := range hums {
+		if rh := v.uncompHumidityToRelativeHumidity(test.raw); rh != test.rh {
+			t.Errorf("raw 0x%04X: got %v%%, want %v%%", test.raw, rh, test.rh)
+		}
+	}
+}
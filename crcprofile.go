@@ -0,0 +1,64 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+)
+
+// CRCFailureProfile make samplesPerLevel measurements in "single shot mode"
+// with each repeatability level and return fraction of measurements
+// failed CRC verification per level. Longer conversion of higher repeatability
+// changes bus timing, so on marginal bus some levels might fail more often
+// than others: profile helps to pick safe repeatability for the hardware.
+// Other read errors (for instance, NACK) are not counted as CRC failures,
+// but bus fault interrupts profiling with error.
+func (v *SHT3X) CRCFailureProfile(i2c I2CBus,
+	samplesPerLevel int) (map[MeasureRepeatability]float64, error) {
+
+	if samplesPerLevel <= 0 {
+		return nil, errors.New("Number of samples must be positive")
+	}
+	profile := make(map[MeasureRepeatability]float64)
+	levels := []MeasureRepeatability{RepeatabilityLow, RepeatabilityMedium,
+		RepeatabilityHigh}
+	for _, precision := range levels {
+		failed := 0
+		for i := 0; i < samplesPerLevel; i++ {
+			_, _, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+			if err != nil {
+				if isBusFault(err) {
+					return nil, err
+				}
+				if errors.Is(err, ErrCRCMismatch) {
+					failed++
+				} else {
+					lg.Debugf("Non-CRC read error ignored: %v", err)
+				}
+			}
+		}
+		profile[precision] = float64(failed) / float64(samplesPerLevel)
+		lg.Debugf("CRC failure rate for %v repeatability = %v", precision,
+			profile[precision])
+	}
+	return profile, nil
+}
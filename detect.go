@@ -0,0 +1,93 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// Longest pause between measurements in "periodic data acquisition mode"
+// (0.5 MPS) plus safety margin, used to detect periodic mode.
+const periodicDetectTimeout = 2*time.Second + 500*time.Millisecond
+
+// DetectPeriodicMode make best effort attempt to find out, whether sensor
+// is running in "periodic data acquisition mode", which is useful after
+// program restart, to decide whether to call Break or Start... method.
+// Sensor has no register exposing current mode, so fetch command is sent
+// and reading is attempted until data appear or longest period (2 sec)
+// expire, thus method could take up to 2.5 sec.
+// Limitations: period and repeatability can't be detected; measurement
+// fetched is consumed and lost; sensor busy with single shot conversion
+// or just not answering is reported as not being in periodic mode.
+// In "single shot mode" sensor might set COMMAND_FAILED flag
+// in status register.
+func (v *SHT3X) DetectPeriodicMode(i2c I2CBus) (bool, error) {
+	lg.Debug("Detecting periodic data acquisition mode...")
+	_, err := i2c.WriteBytes(CMD_PERIOD_FETCH)
+	if err != nil {
+		if isBusFault(err) {
+			return false, wrapBusError(i2c, CMD_PERIOD_FETCH, err)
+		}
+		lg.Debugf("Fetch command rejected: %v", err)
+		return false, nil
+	}
+	const pause = 100 * time.Millisecond
+	// Real clock is used, since pause between attempts is real sleep:
+	// injected clock (SetClock) might never advance and loop would never end.
+	deadline := time.Now().Add(periodicDetectTimeout)
+	for {
+		_, err = v.readDataWithCRCCheck(i2c, 2)
+		if err == nil {
+			return true, nil
+		}
+		if isBusFault(err) {
+			return false, wrapBusError(i2c, CMD_PERIOD_FETCH, err)
+		}
+		if time.Now().Add(pause).After(deadline) {
+			lg.Debugf("No periodic data received: %v", err)
+			return false, nil
+		}
+		time.Sleep(pause)
+	}
+}
+
+// Probe check, whether sensor is present on the bus at the address
+// i2c connection opened with, by reading status register.
+// Return true only if status register is read and CRC is valid.
+// If sensor doesn't acknowledge (NACK, reported as ENXIO), no device
+// is present: false is returned with no error. Any other failure
+// return false with error, so "device present but reply is garbage"
+// (*CRCError) could be told apart from bus faults.
+func (v *SHT3X) Probe(i2c I2CBus) (bool, error) {
+	lg.Debug("Probing sensor...")
+	_, err := v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			lg.Debugf("No sensor found: %v", err)
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
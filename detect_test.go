@@ -0,0 +1,57 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"testing"
+	"time"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestDetectPeriodicModeFrozenClock(t *testing.T) {
+	// no response set, so each read is NACKed
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	sensor := sht3x.NewSHT3X()
+	fixed := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor.SetClock(func() time.Time { return fixed })
+
+	done := make(chan struct{})
+	var periodic bool
+	var err error
+	go func() {
+		periodic, err = sensor.DetectPeriodicMode(bus)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("DetectPeriodicMode didn't return with frozen clock")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if periodic {
+		t.Error("periodic mode detected, want none")
+	}
+}
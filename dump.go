@@ -0,0 +1,116 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// DumpReport contain sensor identity and raw register values
+// as hex strings, which vendor could ask for support or RMA report.
+type DumpReport struct {
+	SerialNumber   string // 32-bit unique serial number
+	StatusReg      string // Status register word
+	AlertHighSet   string // Alert HIGH SET limit word
+	AlertHighClear string // Alert HIGH CLEAR limit word
+	AlertLowClear  string // Alert LOW CLEAR limit word
+	AlertLowSet    string // Alert LOW SET limit word
+	SingleShot     string // Single shot measurement frame, including CRC bytes
+}
+
+// ReadSerialNumber return unique 32-bit sensor serial number, which could
+// be used to identify sensor across buses and hosts. Serial number is read
+// as two 16-bit words, each verified with CRC, combined in big-endian order.
+// CRC mismatch return error, which wrap ErrCRCMismatch.
+func (v *SHT3X) ReadSerialNumber(i2c I2CBus) (uint32, error) {
+	lg.Debug("Reading serial number...")
+	cmd := CMD_READ_SERIAL
+	_, err := i2c.WriteBytes(cmd)
+	if err != nil {
+		return 0, wrapBusError(i2c, cmd, err)
+	}
+	v.setLastCmd(cmd)
+	// No conversion time defined in docs for this command,
+	// so use the same pause as for other non-measurement commands.
+	time.Sleep(time.Millisecond * 1)
+	data, err := v.readDataWithCRCCheck(i2c, 2)
+	if err != nil {
+		return 0, wrapBusError(i2c, cmd, err)
+	}
+	return uint32(data[0])<<16 | uint32(data[1]), nil
+}
+
+// Dump collect serial number, status register, all four alert limits
+// and single shot measurement frame, to provide everything needed
+// for support or RMA report in one call. Measurement frame is taken
+// with high repeatability and returned as is, without CRC verification.
+// Don't call it in "periodic data acquisition mode".
+func (v *SHT3X) Dump(i2c I2CBus) (DumpReport, error) {
+	lg.Debug("Dumping sensor registers...")
+	var report DumpReport
+
+	sn, err := v.ReadSerialNumber(i2c)
+	if err != nil {
+		return DumpReport{}, err
+	}
+	report.SerialNumber = spew.Sprintf("%08X", sn)
+
+	ur, err := v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return DumpReport{}, err
+	}
+	report.StatusReg = spew.Sprintf("%04X", ur)
+
+	alerts := []struct {
+		Cmd   []byte
+		Field *string
+	}{
+		{CMD_ALERT_READ_HIGH_SET, &report.AlertHighSet},
+		{CMD_ALERT_READ_HIGH_CLEAR, &report.AlertHighClear},
+		{CMD_ALERT_READ_LOW_CLEAR, &report.AlertLowClear},
+		{CMD_ALERT_READ_LOW_SET, &report.AlertLowSet},
+	}
+	for _, item := range alerts {
+		u, err := v.readAlertRaw(i2c, item.Cmd)
+		if err != nil {
+			return DumpReport{}, err
+		}
+		*item.Field = spew.Sprintf("%04X", u)
+	}
+
+	err = v.initiateMeasure(i2c, CMD_SINGLE_MEASURE_HIGH,
+		RepeatabilityHigh.GetMeasureTime())
+	if err != nil {
+		return DumpReport{}, err
+	}
+	const frameSize = 2 * (2 + 1)
+	buf := make([]byte, frameSize)
+	_, err = i2c.ReadBytes(buf)
+	if err != nil {
+		return DumpReport{}, wrapBusError(i2c, CMD_SINGLE_MEASURE_HIGH, err)
+	}
+	report.SingleShot = spew.Sprintf("%X", buf)
+
+	return report, nil
+}
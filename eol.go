@@ -0,0 +1,125 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestCriteria define pass/fail criteria of manufacturing end-of-line test.
+type TestCriteria struct {
+	HeatDuration      time.Duration // How long to keep heater on
+	MinTempRise       float32       // Minimum temperature rise caused by heater, Celsius
+	MinRHDrop         float32       // Minimum humidity drop caused by heater, percent
+	RecoveryDuration  time.Duration // How long to wait for sensor to cool down
+	RecoveryTolerance float32       // Max temperature deviation from baseline after recovery, Celsius
+}
+
+// DefaultTestCriteria return criteria suitable for sensor in still air
+// at room temperature.
+func DefaultTestCriteria() TestCriteria {
+	v := TestCriteria{HeatDuration: 10 * time.Second, MinTempRise: 0.5,
+		MinRHDrop: 1, RecoveryDuration: 30 * time.Second, RecoveryTolerance: 0.5}
+	return v
+}
+
+// TestStep keep result of single end-of-line test step.
+type TestStep struct {
+	Name    string
+	Passed  bool
+	Details string
+}
+
+// TestResult keep overall end-of-line test result with all steps detailed.
+type TestResult struct {
+	Passed       bool
+	SerialNumber uint32
+	Baseline     Measurement
+	Heated       Measurement
+	Recovered    Measurement
+	Steps        []TestStep
+}
+
+// addStep register test step result.
+func (v *TestResult) addStep(name string, passed bool, details string) {
+	v.Steps = append(v.Steps, TestStep{Name: name, Passed: passed, Details: details})
+	if !passed {
+		v.Passed = false
+	}
+	lg.Debugf("End-of-line test step %q passed = %v: %s", name, passed, details)
+}
+
+// EndOfLineTest verify freshly assembled sensor works: read serial number,
+// make baseline measurement, pulse heater and confirm temperature rises
+// and humidity drops by expected amounts, then confirm sensor recovers
+// to baseline temperature. Criteria failures are reported in result
+// (with Passed set to false), while error is returned only when sensor
+// communication failed or context was cancelled. Heater is always
+// switched off before return.
+func (v *SHT3X) EndOfLineTest(ctx context.Context, i2c I2CBus,
+	criteria TestCriteria) (TestResult, error) {
+
+	lg.Debug("Running end-of-line test...")
+	result := TestResult{Passed: true}
+
+	sn, err := v.ReadSerialNumber(i2c)
+	if err != nil {
+		return result, err
+	}
+	result.SerialNumber = sn
+	result.addStep("Serial number", true, spew.Sprintf("%08X", sn))
+
+	heated, baseline, err := v.MeasureHeated(ctx, i2c, RepeatabilityHigh, criteria.HeatDuration)
+	if err != nil {
+		return result, err
+	}
+	result.Baseline, result.Heated = baseline, heated
+	result.addStep("Baseline", true, spew.Sprintf("%v*C, %v%%",
+		baseline.Temperature, baseline.RelativeHumidity))
+	rise := heated.Temperature - baseline.Temperature
+	result.addStep("Heater temperature rise", rise >= criteria.MinTempRise,
+		spew.Sprintf("%.2f*C (min %v*C)", rise, criteria.MinTempRise))
+	drop := baseline.RelativeHumidity - heated.RelativeHumidity
+	result.addStep("Heater humidity drop", drop >= criteria.MinRHDrop,
+		spew.Sprintf("%.2f%% (min %v%%)", drop, criteria.MinRHDrop))
+
+	select {
+	// check for termination request
+	case <-ctx.Done():
+		return result, ctx.Err()
+	// wait for sensor to cool down
+	case <-time.After(criteria.RecoveryDuration):
+	}
+	recovered, err := v.ReadMeasurement(i2c, RepeatabilityHigh)
+	if err != nil {
+		return result, err
+	}
+	result.Recovered = recovered
+	dev := abs32(recovered.Temperature - baseline.Temperature)
+	result.addStep("Recovery", dev <= criteria.RecoveryTolerance,
+		spew.Sprintf("%.2f*C from baseline (max %v*C)", dev, criteria.RecoveryTolerance))
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"context"
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestEndOfLineTestHumiditySuppress(t *testing.T) {
+	// Sensor, which humidity doesn't react to heater at all
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_READ_SERIAL, sht3xtest.Words(0x1234, 0x5678))
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_HIGH, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	sensor.SetHeaterHumidityPolicy(sht3x.HeaterHumiditySuppress)
+
+	criteria := sht3x.TestCriteria{MinRHDrop: 1}
+	result, err := sensor.EndOfLineTest(context.Background(), bus, criteria)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Heated.RelativeHumidity != 50 || !result.Heated.HumidityInvalid {
+		t.Errorf("heated humidity = %v%%, invalid = %v, want 50%%, true",
+			result.Heated.RelativeHumidity, result.Heated.HumidityInvalid)
+	}
+	if result.Passed {
+		t.Error("end-of-line test passed, want humidity drop step failed")
+	}
+	for _, step := range result.Steps {
+		if step.Name == "Heater humidity drop" && step.Passed {
+			t.Errorf("humidity drop step passed: %s", step.Details)
+		}
+	}
+}
@@ -21,7 +21,7 @@ func main() {
 	defer logger.FinalizeLogger()
 	// Create new connection to i2c-bus on 0 line with address 0x44.
 	// Use i2cdetect utility to find device address over the i2c-bus
-	i2c, err := i2c.NewI2C(0x44, 0)
+	i2c, err := i2c.NewI2C(sht3x.AddressDefault, 0)
 	if err != nil {
 		lg.Fatal(err)
 	}
@@ -107,6 +107,29 @@ func main() {
 		lg.Fatal(err)
 	}
 
+	lg.Notify("**********************************************************************************************")
+	lg.Notify("*** Periodic data acquisition mode with accelerated response time (ART)")
+	lg.Notify("**********************************************************************************************")
+	err = sensor.StartPeriodicTemperatureAndHumidityMeasure(i2c, sht3x.PeriodicHalfMPS, sht3x.RepeatabilityHigh)
+	if err != nil {
+		lg.Fatal(err)
+	}
+	err = sensor.EnableART(i2c)
+	if err != nil {
+		lg.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		temp, rh, err := sensor.FetchTemperatureAndRelativeHumidity(i2c)
+		if err != nil {
+			lg.Fatal(err)
+		}
+		lg.Infof("Temperature and relative humidity = %v*C, %v%%", temp, rh)
+	}
+	err = sensor.Break(i2c)
+	if err != nil {
+		lg.Fatal(err)
+	}
+
 	lg.Notify("**********************************************************************************************")
 	lg.Notify("*** Get temperature and humidity alert limits")
 	lg.Notify("**********************************************************************************************")
@@ -190,29 +213,10 @@ func main() {
 	// run goroutine waiting for OS termination events, including keyboard Ctrl+C
 	shell.CloseContextOnSignals(cancel, done, signals...)
 
-	err = sensor.SetHeaterStatus(i2c, true)
-	if err != nil {
-		lg.Fatal(err)
-	}
-	hs, err = sensor.GetHeaterStatus(i2c)
-	if err != nil {
-		lg.Fatal(err)
-	}
-	lg.Infof("Heater ON status = %v", hs)
 	pause := time.Second * 10
-	lg.Infof("Waiting %v...", pause)
-	select {
-	// Check for termination request.
-	case <-ctx.Done():
-		err = sensor.SetHeaterStatus(i2c, false)
-		if err != nil {
-			lg.Fatal(err)
-		}
-		lg.Fatal(ctx.Err())
-	// Sleep 10 sec.
-	case <-time.After(pause):
-	}
-	err = sensor.SetHeaterStatus(i2c, false)
+	lg.Infof("Running heater for %v...", pause)
+	// heater is switched off even if termination requested
+	err = sensor.RunHeaterFor(ctx, i2c, pause)
 	if err != nil {
 		lg.Fatal(err)
 	}
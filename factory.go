@@ -0,0 +1,116 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Wide-open alert limits written by FactoryDefaults,
+// which never fire within sensor operating range.
+var defaultAlertConfig = AlertConfig{
+	HighSet:   AlertLimit{Temperature: alertTemperatureMax, RelativeHumidity: alertHumidityMax},
+	HighClear: AlertLimit{Temperature: specTemperatureMax, RelativeHumidity: alertHumidityMax - 1},
+	LowClear:  AlertLimit{Temperature: specTemperatureMin, RelativeHumidity: alertHumidityMin + 1},
+	LowSet:    AlertLimit{Temperature: alertTemperatureMin, RelativeHumidity: alertHumidityMin},
+}
+
+// Alert limits are stored with reduced resolution (9 bits of temperature,
+// 7 bits of humidity), so value read back differ from written one
+// up to single step: 175*C/2^9 and 100%/2^7.
+const (
+	alertTemperatureStep = 175.0 / 512
+	alertHumidityStep    = 100.0 / 128
+)
+
+// FactoryDefaults restore sensor to known baseline state: make soft reset,
+// clear status register, switch heater off and write wide-open alert limits,
+// so no spurious alerts fire. Each step is verified, reading status register
+// or alert limits back. Don't call it in "periodic data acquisition mode".
+// Alert limits are datasheet values, written and verified without
+// calibration offsets defined by SetCalibration.
+func (v *SHT3X) FactoryDefaults(i2c I2CBus) error {
+	lg.Debug("Restoring sensor defaults...")
+	err := v.Reset(i2c)
+	if err != nil {
+		return err
+	}
+
+	err = v.ClearStatusReg(i2c)
+	if err != nil {
+		return err
+	}
+	ur, err := v.ReadStatusReg(i2c)
+	if err != nil {
+		return err
+	}
+	if StatusRegFlag(ur)&RESET_DETECTED != 0 {
+		return errors.New("Status register is not cleared")
+	}
+
+	err = v.SetHeaterStatus(i2c, false)
+	if err != nil {
+		return err
+	}
+	heater, err := v.GetHeaterStatus(i2c)
+	if err != nil {
+		return err
+	}
+	if heater {
+		return errors.New("Heater is not switched off")
+	}
+
+	// defaults are datasheet values, so calibration is bypassed
+	err = v.writeAlertConfig(i2c, defaultAlertConfig, false)
+	if err != nil {
+		return err
+	}
+	// don't restore custom limits after next reset anymore
+	v.mu.Lock()
+	v.alertConfig = nil
+	v.mu.Unlock()
+	ur, err = v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return err
+	}
+	if StatusRegFlag(ur)&(COMMAND_FAILED|WRITE_DATA_CRC_FAILED) != 0 {
+		return errors.New(spew.Sprintf("Alert limits are not accepted, status: %v",
+			StatusRegFlag(ur)))
+	}
+	config, err := v.readAlertConfig(i2c, false)
+	if err != nil {
+		return err
+	}
+	written := []AlertLimit{defaultAlertConfig.HighSet, defaultAlertConfig.HighClear,
+		defaultAlertConfig.LowClear, defaultAlertConfig.LowSet}
+	read := []AlertLimit{config.HighSet, config.HighClear, config.LowClear, config.LowSet}
+	for i := range written {
+		if abs32(written[i].Temperature-read[i].Temperature) > alertTemperatureStep ||
+			abs32(written[i].RelativeHumidity-read[i].RelativeHumidity) > alertHumidityStep {
+			return errors.New(spew.Sprintf("Alert limit read back %v differ from written %v",
+				read[i], written[i]))
+		}
+	}
+	return nil
+}
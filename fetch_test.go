@@ -0,0 +1,60 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFetchRetryPauseHalfMPS(t *testing.T) {
+	const period = PeriodicHalfMPS
+	if d := period.GetWaitDuration(); d != 2*time.Second {
+		t.Fatalf("period = %v, want 2s", d)
+	}
+	retryInterval := period.getRetryDuration()
+	if want := 2 * time.Second / fetchRetryCount; retryInterval != want {
+		t.Errorf("retry interval = %v, want %v", retryInterval, want)
+	}
+	tests := []struct {
+		attempt int
+		pause   time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 400 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{fetchRetryCount, 400 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if pause := period.getFetchRetryPause(test.attempt, retryInterval); pause != test.pause {
+			t.Errorf("attempt %d: pause = %v, want %v", test.attempt, pause, test.pause)
+		}
+	}
+	// worst case: full period, then remaining retries spread across one more period
+	var total time.Duration
+	for attempt := 1; attempt <= fetchRetryCount; attempt++ {
+		total += period.getFetchRetryPause(attempt, retryInterval)
+	}
+	if want := 2*time.Second + (fetchRetryCount-1)*400*time.Millisecond; total != want {
+		t.Errorf("total retry pause = %v, want %v", total, want)
+	}
+}
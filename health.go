@@ -0,0 +1,95 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Health status values reported by HealthHandler.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// HealthReport is a JSON document returned by HealthHandler.
+type HealthReport struct {
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+	StatusReg        uint16    `json:"status_register"`
+	StatusFlags      string    `json:"status_flags"`
+	Temperature      float32   `json:"temperature"`
+	RelativeHumidity float32   `json:"relative_humidity"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// HealthHandler return http handler suitable for "/healthz" endpoint
+// and liveness probes. Each request read status register and make
+// single measurement with low repeatability. Response is a JSON
+// document of HealthReport type with HTTP status 200, when everything
+// is fine, or 503 with "degraded" status, if sensor communication failed.
+// Requests are serialized, so handler is safe to use from
+// concurrent http server goroutines.
+func HealthHandler(sensor *SHT3X, i2c I2CBus) http.HandlerFunc {
+	var mutex sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		report := checkHealth(sensor, i2c)
+		mutex.Unlock()
+
+		code := http.StatusOK
+		if report.Status != HealthOK {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		err := json.NewEncoder(w).Encode(report)
+		if err != nil {
+			lg.Error(err)
+		}
+	}
+}
+
+// checkHealth read status register and make measurement to fill in HealthReport.
+func checkHealth(sensor *SHT3X, i2c I2CBus) *HealthReport {
+	report := &HealthReport{Status: HealthOK, Timestamp: sensor.now()}
+	ur, err := sensor.ReadStatusRegFresh(i2c)
+	if err != nil {
+		report.Status = HealthDegraded
+		report.Error = err.Error()
+		return report
+	}
+	report.StatusReg = ur
+	report.StatusFlags = StatusRegFlag(ur).String()
+	temp, rh, err := sensor.ReadTemperatureAndRelativeHumidity(i2c, RepeatabilityLow)
+	if err != nil {
+		report.Status = HealthDegraded
+		report.Error = err.Error()
+		return report
+	}
+	report.Temperature = temp
+	report.RelativeHumidity = rh
+	return report
+}
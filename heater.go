@@ -0,0 +1,118 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MeasureHeated make unheated baseline measurement, then switch heater on,
+// wait heatDuration and make heated measurement while heater is still on.
+// Heater is switched off before return in any case, including
+// context cancellation. Difference between heated and baseline humidity
+// could be used to detect condensation on the sensor itself:
+// wet sensor shows much smaller humidity drop, than dry one.
+// Heated measurement always report humidity measured (with HumidityInvalid
+// flag set), regardless of HeaterHumiditySuppress policy.
+func (v *SHT3X) MeasureHeated(ctx context.Context, i2c I2CBus,
+	precision MeasureRepeatability, heatDuration time.Duration) (heated, baseline Measurement, err error) {
+
+	lg.Debug("Measuring with heater on...")
+	baseline, err = v.ReadMeasurement(i2c, precision)
+	if err != nil {
+		return Measurement{}, Measurement{}, err
+	}
+	err = v.SetHeaterStatus(i2c, true)
+	if err != nil {
+		return Measurement{}, Measurement{}, err
+	}
+	defer func() {
+		err2 := v.SetHeaterStatus(i2c, false)
+		if err2 != nil && err == nil {
+			err = err2
+		}
+	}()
+	select {
+	// check for termination request
+	case <-ctx.Done():
+		return Measurement{}, Measurement{}, ctx.Err()
+	// wait for heater to warm up sensor
+	case <-time.After(heatDuration):
+	}
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return Measurement{}, Measurement{}, err
+	}
+	heated = v.buildMeasurement(ut, urh, false)
+	return heated, baseline, nil
+}
+
+// ErrHeaterStillOn is returned by RunHeaterFor, if heater remain enabled
+// according to status register after it was switched off.
+var ErrHeaterStillOn = errors.New("Heater is still on after switching off")
+
+// RunHeaterFor enable heater, wait for duration specified and
+// disable heater. Waiting could be interrupted with context cancellation,
+// in that case context error is returned. Heater is switched off
+// in any case, even if context is cancelled or any error occurs,
+// since heater left on bias measurements and heat sensor up.
+// Finally, heater status is read back to verify heater is really off.
+func (v *SHT3X) RunHeaterFor(ctx context.Context, i2c I2CBus,
+	duration time.Duration) (err error) {
+
+	lg.Debugf("Running heater for %v...", duration)
+	// defer before enabling, since failed write might still reach sensor
+	defer func() {
+		err2 := v.SetHeaterStatus(i2c, false)
+		if err2 != nil {
+			if err == nil {
+				err = err2
+			} else {
+				lg.Error(err2)
+			}
+			return
+		}
+		on, err2 := v.GetHeaterStatus(i2c)
+		if err2 != nil {
+			if err == nil {
+				err = err2
+			}
+			return
+		}
+		if on {
+			err = ErrHeaterStillOn
+		}
+	}()
+	err = v.SetHeaterStatus(i2c, true)
+	if err != nil {
+		return err
+	}
+	select {
+	// check for termination request
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+// ReadHumidityFast returns relative humidity obtained from sensor in "single
+// shot mode" with minimal latency, for humidity-centric applications polling
+// at high rate (leak or condensation detection). Low repeatability is used
+// (shortest conversion, 4.5 ms), data is read into stack buffers and
+// temperature is neither converted nor rounded, though its CRC is verified.
+// Call duration is given by RepeatabilityLow.GetReadDuration; don't poll
+// faster than MaxSingleShotRate(RepeatabilityLow) allows, since sustained
+// high measurement rate cause sensor self-heating, which slightly
+// decrease humidity reported.
+func (v *SHT3X) ReadHumidityFast(i2c I2CBus) (float32, error) {
+	cmd := CMD_SINGLE_MEASURE_LOW
+	err := v.initiateMeasure(i2c, cmd, RepeatabilityLow.GetMeasureTime())
+	if err != nil {
+		return 0, err
+	}
+	var buf [2 * crcBlockSize]byte
+	var data [2]uint16
+	err = v.readDataWithCRCCheckToBuf(i2c, buf[:], data[:])
+	if err != nil {
+		return 0, wrapBusError(i2c, cmd, err)
+	}
+	return v.uncompHumidityToRelativeHumidity(data[1]), nil
+}
@@ -0,0 +1,412 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Measurement keep temperature and relative humidity
+// obtained from sensor at once.
+type Measurement struct {
+	Temperature      float32 `json:"temperature_c"`     // Celsius
+	RelativeHumidity float32 `json:"relative_humidity"` // Percent
+	// Time data was read from sensor: it's captured right after
+	// successful i2c read, so fetch retries and consumer delays
+	// don't affect it.
+	Timestamp time.Time `json:"timestamp"`
+	// Heater is on, or was switched off within settle window,
+	// so values might be inflated by residual heat.
+	HeaterBiasSuspected bool `json:"heater_bias_suspected,omitempty"`
+	// Quality assessment, nil if measurement was not assessed.
+	Quality *QualityInfo `json:"quality,omitempty"`
+	// Measurement uncertainty, zero if not estimated.
+	TempUncertaintyC float32 `json:"temperature_uncertainty_c,omitempty"`
+	RHUncertaintyPct float32 `json:"relative_humidity_uncertainty,omitempty"`
+	// Raw sensor values, zero unless enabled with SetIncludeRawValues.
+	RawTemp     uint16 `json:"raw_temperature,omitempty"`
+	RawHumidity uint16 `json:"raw_humidity,omitempty"`
+	// Heater was on during measurement, so humidity doesn't reflect
+	// ambient one (see SetHeaterHumidityPolicy).
+	HumidityInvalid bool `json:"humidity_invalid,omitempty"`
+}
+
+// MarshalJSON implement json.Marshaler interface: in addition to fields
+// it include dew point calculated with DewPoint, unless humidity is invalid.
+func (v Measurement) MarshalJSON() ([]byte, error) {
+	// measurement has no methods, so it's marshaled in default way
+	type measurement Measurement
+	var dewPoint *float32
+	if !v.HumidityInvalid {
+		dp := round32(DewPoint(v.Temperature, v.RelativeHumidity), 2)
+		dewPoint = &dp
+	}
+	return json.Marshal(struct {
+		measurement
+		DewPoint *float32 `json:"dew_point_c,omitempty"`
+	}{measurement(v), dewPoint})
+}
+
+// QualityInfo describe how trustworthy measurement is.
+type QualityInfo struct {
+	Score         int                  `json:"score"`           // 0..100, higher is better
+	Repeatability MeasureRepeatability `json:"repeatability"`   // Repeatability used to measure
+	Attempts      int                  `json:"attempts"`        // Read attempts made, including successful one
+	StatusReg     uint16               `json:"status_register"` // Status register read right after measurement
+}
+
+// HeaterHumidityPolicy define how humidity is reported,
+// when measurement is made with heater switched on.
+type HeaterHumidityPolicy int
+
+const (
+	HeaterHumidityFlag     HeaterHumidityPolicy = iota + 1 // Report humidity, set HumidityInvalid flag
+	HeaterHumiditySuppress                                 // Report zero humidity, set HumidityInvalid flag
+)
+
+// String define stringer interface.
+func (v HeaterHumidityPolicy) String() string {
+	switch v {
+	case HeaterHumidityFlag:
+		return "Flag"
+	case HeaterHumiditySuppress:
+		return "Suppress"
+	default:
+		return "<unknown>"
+	}
+}
+
+// SetHeaterHumidityPolicy define how humidity measured with heater
+// switched on (by SetHeaterStatus call) is reported in Measurement.
+// Heater drops humidity dramatically, so such values shouldn't be logged
+// as ambient humidity. Default policy is HeaterHumidityFlag.
+func (v *SHT3X) SetHeaterHumidityPolicy(policy HeaterHumidityPolicy) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.heaterPolicy = policy
+}
+
+// SetIncludeRawValues enable or disable preserving of raw sensor values
+// in RawTemp and RawHumidity fields of each Measurement produced,
+// to store exact sensor output and reconvert it later.
+func (v *SHT3X) SetIncludeRawValues(include bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.includeRaw = include
+}
+
+// newMeasurement build Measurement from raw values just obtained from sensor.
+// Must be called right after read, since it take timestamp.
+func (v *SHT3X) newMeasurement(ut, urh uint16) Measurement {
+	return v.buildMeasurement(ut, urh, true)
+}
+
+// buildMeasurement is newMeasurement, where suppress set to false
+// make HeaterHumiditySuppress policy ignored, so humidity measured
+// with heater on is reported as is (still flagged with HumidityInvalid).
+// It's used, when heated measurement is made on purpose.
+func (v *SHT3X) buildMeasurement(ut, urh uint16, suppress bool) Measurement {
+	lg.Debugf("Temperature and humidity uncompensated = %v, %v", ut, urh)
+	m := Measurement{Temperature: v.uncompTemperatureToCelsius(ut),
+		RelativeHumidity: v.uncompHumidityToRelativeHumidity(urh)}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	m.Timestamp = v.nowLocked()
+	m.HeaterBiasSuspected = v.heaterBiasSuspected()
+	if v.includeRaw {
+		m.RawTemp, m.RawHumidity = ut, urh
+	}
+	if v.heaterOn {
+		m.HumidityInvalid = true
+		if suppress && v.heaterPolicy == HeaterHumiditySuppress {
+			m.RelativeHumidity = 0
+		}
+	}
+	return m
+}
+
+// ReadMeasurement returns humidity and temperature obtained
+// from sensor in "single shot mode" as a Measurement.
+func (v *SHT3X) ReadMeasurement(i2c I2CBus,
+	precision MeasureRepeatability) (Measurement, error) {
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return Measurement{}, err
+	}
+	return v.newMeasurement(ut, urh), nil
+}
+
+// DetailedMeasurement keep raw sensor values along with
+// temperature and relative humidity converted from them.
+type DetailedMeasurement struct {
+	RawTemperature   uint16
+	RawHumidity      uint16
+	Temperature      float32 // Celsius
+	RelativeHumidity float32 // Percent
+}
+
+// ReadDetailed make measurement in "single shot mode" and return
+// both raw values and values converted from them, which is
+// guaranteed to be consistent, to debug calibration.
+func (v *SHT3X) ReadDetailed(i2c I2CBus,
+	precision MeasureRepeatability) (DetailedMeasurement, error) {
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return DetailedMeasurement{}, err
+	}
+	m := DetailedMeasurement{RawTemperature: ut, RawHumidity: urh,
+		Temperature:      v.uncompTemperatureToCelsius(ut),
+		RelativeHumidity: v.uncompHumidityToRelativeHumidity(urh)}
+	return m, nil
+}
+
+// ReadRawAndCalibrated make measurement in "single shot mode" and return
+// temperature and relative humidity converted from the same raw values
+// both without and with calibration offsets (defined by SetCalibration)
+// applied, to confirm offsets take effect.
+func (v *SHT3X) ReadRawAndCalibrated(i2c I2CBus, precision MeasureRepeatability) (rawTempC,
+	calibratedTempC, rawRH, calibratedRH float32, err error) {
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	rawTempC, rawRH = v.uncompToUncalibrated(ut, urh)
+	calibratedTempC = v.uncompTemperatureToCelsius(ut)
+	calibratedRH = v.uncompHumidityToRelativeHumidity(urh)
+	return rawTempC, calibratedTempC, rawRH, calibratedRH, nil
+}
+
+// FetchMeasurement returns humidity and temperature obtained
+// from sensor in "periodic data acquisition mode" as a Measurement.
+// Call is limited by default timeout, the same as for FetchTemperatureAndRelativeHumidity.
+func (v *SHT3X) FetchMeasurement(i2c I2CBus) (Measurement, error) {
+	ut, urh, err := v.FetchUncompTemperatureAndHumidity(i2c)
+	if err != nil {
+		return Measurement{}, err
+	}
+	return v.newMeasurement(ut, urh), nil
+}
+
+// ReadIfChanged make measurement in "single shot mode" and compare it
+// with the last measurement reported as changed by this method.
+// If both temperature and humidity are within tolerance, changed is false,
+// though measurement is returned anyway. Otherwise measurement
+// is remembered as the last reported one and changed is true.
+// Very first call always report change.
+func (v *SHT3X) ReadIfChanged(i2c I2CBus, precision MeasureRepeatability,
+	tempTol, rhTol float32) (m Measurement, changed bool, err error) {
+
+	m, err = v.ReadMeasurement(i2c, precision)
+	if err != nil {
+		return Measurement{}, false, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.lastEmitted != nil &&
+		abs32(m.Temperature-v.lastEmitted.Temperature) <= tempTol &&
+		abs32(m.RelativeHumidity-v.lastEmitted.RelativeHumidity) <= rhTol {
+		return m, false, nil
+	}
+	v.lastEmitted = &m
+	return m, true, nil
+}
+
+// ReadMeasurementWithStatus make measurement in "single shot mode" and
+// immediately read status register afterwards, to get status snapshot
+// as close in time to measurement as possible. Gap is the time elapsed
+// between measurement data and status register being read, so caller
+// could judge how tightly they are correlated.
+func (v *SHT3X) ReadMeasurementWithStatus(i2c I2CBus,
+	precision MeasureRepeatability) (m Measurement, status uint16, gap time.Duration, err error) {
+
+	m, err = v.ReadMeasurement(i2c, precision)
+	if err != nil {
+		return Measurement{}, 0, 0, err
+	}
+	status, err = v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return Measurement{}, 0, 0, err
+	}
+	gap = v.now().Sub(m.Timestamp)
+	return m, status, gap, nil
+}
+
+// Number of read attempts made by MeasurePrecise.
+const preciseAttempts = 3
+
+// Range of values which sensor specification is guaranteed for.
+const (
+	specTemperatureMin = -40
+	specTemperatureMax = 125
+	specHumidityMin    = 0
+	specHumidityMax    = 100
+)
+
+// MeasurePrecise make "gold standard" measurement, suitable for calibration
+// and reference purposes: use high repeatability, retry failed reads (except
+// bus faults), read status register to confirm last command didn't fail,
+// and verify values are within sensor specified range. Returned measurement
+// is annotated with QualityInfo, where score is decreased for retries (10 per
+// retry), for reset detected since status register was cleared (20)
+// and for suspected heater bias (50).
+func (v *SHT3X) MeasurePrecise(ctx context.Context, i2c I2CBus) (Measurement, error) {
+	lg.Debug("Making precise measurement...")
+	var m Measurement
+	var err error
+	attempts := 0
+	for attempts < preciseAttempts {
+		if err2 := ctx.Err(); err2 != nil {
+			return Measurement{}, err2
+		}
+		attempts++
+		m, err = v.ReadMeasurement(i2c, RepeatabilityHigh)
+		if err == nil || isBusFault(err) {
+			break
+		}
+		lg.Debugf("Precise measurement attempt %d of %d failed, retry immediately: %v",
+			attempts, preciseAttempts, err)
+	}
+	if err != nil {
+		return Measurement{}, err
+	}
+
+	err = v.assessQuality(i2c, &m, RepeatabilityHigh, attempts)
+	if err != nil {
+		return Measurement{}, err
+	}
+	return m, nil
+}
+
+// assessQuality read status register to confirm last command didn't fail,
+// verify values are within sensor specified range and annotate measurement
+// with QualityInfo, scored the same way as described for MeasurePrecise.
+func (v *SHT3X) assessQuality(i2c I2CBus, m *Measurement,
+	precision MeasureRepeatability, attempts int) error {
+
+	ur, err := v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return err
+	}
+	flags := StatusRegFlag(ur)
+	if flags&COMMAND_FAILED != 0 {
+		return errors.New("Sensor reports last command failed")
+	}
+	if m.Temperature < specTemperatureMin || m.Temperature > specTemperatureMax ||
+		m.RelativeHumidity < specHumidityMin || m.RelativeHumidity > specHumidityMax {
+		return errors.New(spew.Sprintf(
+			"Measurement %v*C, %v%% is out of sensor specified range",
+			m.Temperature, m.RelativeHumidity))
+	}
+
+	score := 100 - 10*(attempts-1)
+	if flags&RESET_DETECTED != 0 {
+		score -= 20
+	}
+	if m.HeaterBiasSuspected {
+		score -= 50
+	}
+	if score < 0 {
+		score = 0
+	}
+	m.Quality = &QualityInfo{Score: score, Repeatability: precision,
+		Attempts: attempts, StatusReg: ur}
+	return nil
+}
+
+// ReadUntilQuality make measurements in "single shot mode" until one
+// reaches minimum quality score (see MeasurePrecise for scoring rules),
+// or maxAttempts measurements are made. If none qualify, the best
+// measurement seen is returned without error, so caller should check
+// Quality.Score of result. Error is returned only if no measurement
+// succeeded at all, or bus fault detected.
+func (v *SHT3X) ReadUntilQuality(ctx context.Context, i2c I2CBus,
+	precision MeasureRepeatability, minQuality int, maxAttempts int) (Measurement, error) {
+
+	var best *Measurement
+	var lastErr error
+	failed := 0
+	for i := 0; i < maxAttempts; i++ {
+		if err := ctx.Err(); err != nil {
+			return Measurement{}, err
+		}
+		m, err := v.ReadMeasurement(i2c, precision)
+		if err == nil {
+			err = v.assessQuality(i2c, &m, precision, failed+1)
+		}
+		if err != nil {
+			if isBusFault(err) {
+				return Measurement{}, err
+			}
+			lg.Debugf("Measurement attempt %d of %d failed, retry immediately: %v",
+				i+1, maxAttempts, err)
+			lastErr = err
+			failed++
+			continue
+		}
+		failed = 0
+		lg.Debugf("Measurement attempt %d quality score = %d", i+1, m.Quality.Score)
+		if m.Quality.Score >= minQuality {
+			return m, nil
+		}
+		if best == nil || m.Quality.Score > best.Quality.Score {
+			best = &m
+		}
+	}
+	if best != nil {
+		return *best, nil
+	}
+	if lastErr == nil {
+		lastErr = errors.New("No measurement attempts made")
+	}
+	return Measurement{}, lastErr
+}
+
+// ReadMeasurementWithUncertainty make measurement in "single shot mode"
+// and estimate its uncertainty, combining in quadrature repeatability
+// (see EffectiveResolution) with typical accuracy tolerance of sensor model
+// (0.2*C, 2%RH for SHT30/SHT31; 0.1*C, 1.5%RH for SHT35/SHT85).
+// Values are returned in
+// TempUncertaintyC and RHUncertaintyPct fields.
+func (v *SHT3X) ReadMeasurementWithUncertainty(i2c I2CBus,
+	precision MeasureRepeatability) (Measurement, error) {
+
+	m, err := v.ReadMeasurement(i2c, precision)
+	if err != nil {
+		return Measurement{}, err
+	}
+	tempNoise, rhNoise := precision.EffectiveResolution()
+	tempAccuracy, rhAccuracy := v.GetModel().Accuracy()
+	m.TempUncertaintyC = float32(round64(math.Hypot(float64(tempAccuracy),
+		float64(tempNoise)), 2))
+	m.RHUncertaintyPct = float32(round64(math.Hypot(float64(rhAccuracy),
+		float64(rhNoise)), 2))
+	return m, nil
+}
@@ -0,0 +1,62 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+	"sort"
+)
+
+// median return median of values, averaging two middle ones
+// for even count. Values slice is sorted in place.
+func median(values []float32) float32 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	n := len(values)
+	if n%2 == 0 {
+		return (values[n/2-1] + values[n/2]) / 2
+	}
+	return values[n/2]
+}
+
+// ReadMedian make specified number of measurements in "single shot mode"
+// and return median temperature and relative humidity, which is more robust,
+// than average, when single measurement is wildly wrong (electrical spike).
+// Medians are taken independently, so temperature and humidity
+// might come from different measurements. Any read error abort call.
+func (v *SHT3X) ReadMedian(i2c I2CBus, precision MeasureRepeatability,
+	samples int) (float32, float32, error) {
+
+	if samples < 1 {
+		return 0, 0, errors.New("Number of samples must be positive")
+	}
+	lg.Debugf("Measuring median of %d samples...", samples)
+	temps := make([]float32, samples)
+	rhs := make([]float32, samples)
+	for i := 0; i < samples; i++ {
+		temp, rh, err := v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+		if err != nil {
+			return 0, 0, err
+		}
+		temps[i], rhs[i] = temp, rh
+	}
+	return round32(median(temps), 2), round32(median(rhs), 2), nil
+}
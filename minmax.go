@@ -0,0 +1,145 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"sync"
+	"time"
+)
+
+// minMaxSample keep single measurement registered by MinMaxTracker.
+type minMaxSample struct {
+	Time        time.Time
+	Temperature float32
+	Humidity    float32
+}
+
+// MinMaxTracker wrap sensor to keep track of minimum and maximum
+// temperature and relative humidity observed within time window.
+// Each read made via tracker update statistics; measurements
+// older than window are trimmed out. Tracker is safe for concurrent use.
+type MinMaxTracker struct {
+	sensor *SHT3X
+	window time.Duration
+	// mu guard samples
+	mu      sync.Mutex
+	samples []minMaxSample
+}
+
+// NewMinMaxTracker return new tracker for sensor instance.
+// Window define how long measurements participate in statistics,
+// zero or negative window value means "keep everything".
+func NewMinMaxTracker(sensor *SHT3X, window time.Duration) *MinMaxTracker {
+	v := &MinMaxTracker{sensor: sensor, window: window}
+	return v
+}
+
+// ReadTemperatureAndRelativeHumidity make measurement in "single shot mode"
+// and register obtained values in statistics.
+func (v *MinMaxTracker) ReadTemperatureAndRelativeHumidity(i2c I2CBus,
+	precision MeasureRepeatability) (float32, float32, error) {
+
+	temp, rh, err := v.sensor.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, err
+	}
+	v.Add(temp, rh)
+	return temp, rh, nil
+}
+
+// FetchTemperatureAndRelativeHumidity read results of "periodic data acquisition mode"
+// and register obtained values in statistics.
+func (v *MinMaxTracker) FetchTemperatureAndRelativeHumidity(i2c I2CBus) (float32, float32, error) {
+	temp, rh, err := v.sensor.FetchTemperatureAndRelativeHumidity(i2c)
+	if err != nil {
+		return 0, 0, err
+	}
+	v.Add(temp, rh)
+	return temp, rh, nil
+}
+
+// Add register temperature and relative humidity obtained elsewhere.
+func (v *MinMaxTracker) Add(temp, rh float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.samples = append(v.samples, minMaxSample{Time: v.sensor.now(),
+		Temperature: temp, Humidity: rh})
+	v.trim()
+}
+
+// trim remove measurements which fall outside of time window.
+// Must be called with mu locked.
+func (v *MinMaxTracker) trim() {
+	if v.window <= 0 {
+		return
+	}
+	from := v.sensor.now().Add(-v.window)
+	i := 0
+	for i < len(v.samples) && v.samples[i].Time.Before(from) {
+		i++
+	}
+	v.samples = v.samples[i:]
+}
+
+// Count return number of measurements in time window.
+func (v *MinMaxTracker) Count() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.trim()
+	return len(v.samples)
+}
+
+// MinMax return minimum and maximum temperature and relative humidity
+// observed within time window. If no measurements found in the window,
+// all values returned are zero: use Count to distinguish this case.
+func (v *MinMaxTracker) MinMax() (tempMin, tempMax, rhMin, rhMax float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.trim()
+	if len(v.samples) == 0 {
+		return 0, 0, 0, 0
+	}
+	tempMin, tempMax = v.samples[0].Temperature, v.samples[0].Temperature
+	rhMin, rhMax = v.samples[0].Humidity, v.samples[0].Humidity
+	for _, item := range v.samples[1:] {
+		if item.Temperature < tempMin {
+			tempMin = item.Temperature
+		}
+		if item.Temperature > tempMax {
+			tempMax = item.Temperature
+		}
+		if item.Humidity < rhMin {
+			rhMin = item.Humidity
+		}
+		if item.Humidity > rhMax {
+			rhMax = item.Humidity
+		}
+	}
+	return tempMin, tempMax, rhMin, rhMax
+}
+
+// Reset clear statistics.
+func (v *MinMaxTracker) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.samples = nil
+}
@@ -0,0 +1,57 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	sht3x "github.com/d2r2/go-sht3x"
+)
+
+// Run with "go test -race" to verify tracker shared between
+// goroutines doesn't race on samples.
+func TestMinMaxTrackerConcurrent(t *testing.T) {
+	tracker := sht3x.NewMinMaxTracker(sht3x.NewSHT3X(), time.Hour)
+	const goroutines, adds = 8, 100
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < adds; j++ {
+				tracker.Add(float32(i), float32(j))
+				tracker.MinMax()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := tracker.Count(); n != goroutines*adds {
+		t.Errorf("count = %d, want %d", n, goroutines*adds)
+	}
+	tempMin, tempMax, rhMin, rhMax := tracker.MinMax()
+	if tempMin != 0 || tempMax != goroutines-1 || rhMin != 0 || rhMax != adds-1 {
+		t.Errorf("got %v..%v*C, %v..%v%%, want 0..%v*C, 0..%v%%",
+			tempMin, tempMax, rhMin, rhMax, goroutines-1, adds-1)
+	}
+}
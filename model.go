@@ -0,0 +1,84 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+// Model identify sensor variant. All of them share the same
+// command set, but differ in accuracy.
+type Model int
+
+const (
+	ModelSHT3X Model = iota + 1 // Unspecified SHT3x, SHT30/SHT31 accuracy assumed
+	ModelSHT30                  // SHT30
+	ModelSHT31                  // SHT31
+	ModelSHT35                  // SHT35
+	ModelSHT85                  // SHT85, always use address 0x44
+)
+
+// String define stringer interface.
+func (v Model) String() string {
+	switch v {
+	case ModelSHT3X:
+		return "SHT3x"
+	case ModelSHT30:
+		return "SHT30"
+	case ModelSHT31:
+		return "SHT31"
+	case ModelSHT35:
+		return "SHT35"
+	case ModelSHT85:
+		return "SHT85"
+	default:
+		return "<unknown>"
+	}
+}
+
+// Accuracy return typical accuracy tolerance of temperature in Celsius
+// and relative humidity in percent, according to specification
+// of the model.
+func (v Model) Accuracy() (tempC, rhPct float32) {
+	switch v {
+	case ModelSHT35, ModelSHT85:
+		return 0.1, 1.5
+	default:
+		return 0.2, 2
+	}
+}
+
+// NewSHT3XWithModel return new sensor instance of model specified.
+func NewSHT3XWithModel(model Model) *SHT3X {
+	v := NewSHT3X()
+	v.model = model
+	return v
+}
+
+// NewSHT85 return new SHT85 sensor instance. SHT85 is protocol
+// compatible with SHT3x, so all methods work the same way.
+func NewSHT85() *SHT3X {
+	return NewSHT3XWithModel(ModelSHT85)
+}
+
+// GetModel return sensor model.
+func (v *SHT3X) GetModel() Model {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.model
+}
@@ -0,0 +1,151 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// PayloadFormat define compact JSON payload layout for MQTT publishing:
+// field names (could be adapted to topic naming conventions) and units.
+type PayloadFormat struct {
+	SensorField      string
+	TemperatureField string
+	HumidityField    string
+	TimestampField   string // Unix time in milliseconds
+	Fahrenheit       bool   // Report temperature in Fahrenheit instead of Celsius
+}
+
+// DefaultPayloadFormat return payload format with field names
+// "sensor", "temp_c", "rh_pct", "ts".
+func DefaultPayloadFormat() PayloadFormat {
+	v := PayloadFormat{SensorField: "sensor", TemperatureField: "temp_c",
+		HumidityField: "rh_pct", TimestampField: "ts"}
+	return v
+}
+
+// SensorID return sensor identifier built from i2c bus and address,
+// for instance "i2c-1-0x44". Serial number obtained with ReadSerialNumber
+// could be used instead, if it's unique identity required across buses and hosts.
+func SensorID(i2c I2CBus) string {
+	return spew.Sprintf("i2c-%d-0x%02x", i2c.GetBus(), i2c.GetAddr())
+}
+
+// Marshal build compact JSON payload from measurement.
+func (v PayloadFormat) Marshal(sensorID string, m Measurement) ([]byte, error) {
+	temp := m.Temperature
+	if v.Fahrenheit {
+		temp = Celsius(temp).Fahrenheit()
+	}
+	payload := map[string]interface{}{
+		v.SensorField:      sensorID,
+		v.TemperatureField: temp,
+		v.HumidityField:    m.RelativeHumidity,
+		v.TimestampField:   m.Timestamp.UnixNano() / 1e6,
+	}
+	return json.Marshal(payload)
+}
+
+// Write build compact JSON payload from measurement and write it to w.
+// Wrap MQTT client publish call with io.Writer to send payload to the topic.
+func (v PayloadFormat) Write(w io.Writer, sensorID string, m Measurement) error {
+	payload, err := v.Marshal(sensorID, m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+// Unmarshal decode compact JSON payload built by Marshal back into
+// sensor identifier and measurement, so receiving side could restore typed
+// Measurement for further processing. Only fields transferred in payload
+// are restored; Fahrenheit temperature is converted back to Celsius.
+func (v PayloadFormat) Unmarshal(payload []byte) (sensorID string, m Measurement, err error) {
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	err = dec.Decode(&fields)
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	id, ok := fields[v.SensorField].(string)
+	if !ok {
+		return "", Measurement{}, errors.New(spew.Sprintf(
+			"Payload field %q is missing or not a string", v.SensorField))
+	}
+	number := func(name string) (json.Number, error) {
+		n, ok := fields[name].(json.Number)
+		if !ok {
+			return "", errors.New(spew.Sprintf(
+				"Payload field %q is missing or not a number", name))
+		}
+		return n, nil
+	}
+	n, err := number(v.TemperatureField)
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	temp, err := n.Float64()
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	n, err = number(v.HumidityField)
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	rh, err := n.Float64()
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	n, err = number(v.TimestampField)
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	ts, err := n.Int64()
+	if err != nil {
+		return "", Measurement{}, err
+	}
+	m.Temperature = float32(temp)
+	if v.Fahrenheit {
+		m.Temperature = UnitFahrenheit.toCelsius(m.Temperature)
+	}
+	m.RelativeHumidity = float32(rh)
+	m.Timestamp = time.Unix(0, ts*int64(time.Millisecond))
+	return id, m, nil
+}
+
+// UnmarshalMeasurement decode measurement from compact JSON payload
+// built with default payload format (see DefaultPayloadFormat).
+func UnmarshalMeasurement(payload []byte) (Measurement, error) {
+	_, m, err := DefaultPayloadFormat().Unmarshal(payload)
+	if err != nil {
+		return Measurement{}, err
+	}
+	return m, nil
+}
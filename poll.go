@@ -0,0 +1,177 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"time"
+)
+
+// PollTarget define sensor and i2c connection to it.
+type PollTarget struct {
+	Sensor *SHT3X
+	I2C    I2CBus
+}
+
+// PollResult keep measurement and status register obtained from single
+// sensor by PollAll call. Err is not nil, if sensor polling failed.
+type PollResult struct {
+	Measurement Measurement
+	StatusReg   uint16
+	Err         error
+}
+
+// PollAll make measurement in "single shot mode" and read status register
+// for each sensor. Measurement commands are sent to all sensors first,
+// so conversion runs in parallel and total time is close to single
+// conversion time plus bus transfers. Read buffers are shared across sensors
+// to minimize allocations. Result slice has the same order as targets.
+// Each sensor is handled the same way, as by ReadMeasurement followed by
+// ReadStatusRegFresh: measurement timeout, stuck values detection,
+// timing statistics and sensor reset detection apply.
+func PollAll(targets []PollTarget, precision MeasureRepeatability) []PollResult {
+	results := make([]PollResult, len(targets))
+	deadlines := make([]time.Time, len(targets))
+	cmd := getSingleMeasurementCommand(precision)
+	pause := precision.GetMeasureTime()
+	for i, item := range targets {
+		item.Sensor.mu.Lock()
+		timeout := item.Sensor.measurementTimeout
+		item.Sensor.mu.Unlock()
+		start := time.Now()
+		if timeout > 0 {
+			deadlines[i] = start.Add(timeout)
+			// Don't send command, if conversion can't complete in time
+			if singleShotBusOverhead+pause > timeout {
+				results[i].Err = context.DeadlineExceeded
+				continue
+			}
+		}
+		_, err := item.I2C.WriteBytes(cmd)
+		dur := time.Since(start)
+		item.Sensor.mu.Lock()
+		item.Sensor.timing.addWrite(dur)
+		item.Sensor.mu.Unlock()
+		if err != nil {
+			results[i].Err = wrapBusError(item.I2C, cmd, err)
+			continue
+		}
+		item.Sensor.setLastCmd(cmd)
+	}
+	// Wait according to conversion time specification
+	start := time.Now()
+	time.Sleep(pause)
+	wait := time.Since(start)
+
+	var buf [2 * crcBlockSize]byte
+	var data [2]uint16
+	for i, item := range targets {
+		if results[i].Err != nil {
+			continue
+		}
+		item.Sensor.mu.Lock()
+		item.Sensor.timing.addWait(wait)
+		item.Sensor.mu.Unlock()
+		err := item.Sensor.readDataWithCRCCheckToBuf(item.I2C, buf[:], data[:])
+		if err != nil {
+			results[i].Err = wrapBusError(item.I2C, cmd, err)
+			continue
+		}
+		// read might be delayed by other sensors, clock stretching or bus contention
+		if !deadlines[i].IsZero() && time.Now().After(deadlines[i]) {
+			results[i].Err = context.DeadlineExceeded
+			continue
+		}
+		err = item.Sensor.checkStuck(data[0], data[1])
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Measurement = item.Sensor.newMeasurement(data[0], data[1])
+
+		_, err = item.I2C.WriteBytes(CMD_READ_STATUS_REG)
+		if err != nil {
+			results[i].Err = wrapBusError(item.I2C, CMD_READ_STATUS_REG, err)
+			continue
+		}
+		item.Sensor.setLastCmd(CMD_READ_STATUS_REG)
+		err = item.Sensor.readDataWithCRCCheckToBuf(item.I2C, buf[:crcBlockSize], data[:1])
+		if err != nil {
+			results[i].Err = wrapBusError(item.I2C, CMD_READ_STATUS_REG, err)
+			continue
+		}
+		results[i].StatusReg = data[0]
+		err = item.Sensor.updateStatusReg(item.I2C, data[0])
+		if err != nil {
+			results[i].Err = err
+		}
+	}
+	return results
+}
+
+// StartPeriodicSynchronized start "periodic data acquisition mode" on all sensors
+// as close together as possible, so their periodic samples are roughly
+// phase-aligned. Commands are sent back to back without conversion pauses;
+// returned offsets are the time each sensor was started relative to the first one.
+// Achievable alignment is limited by bus serialization: each start command
+// takes about 0.3 ms at 100 kHz plus i2c driver call overhead, so the last
+// of N sensors lags by roughly N times 0.3..1 ms. Sensor internal oscillators
+// drift independently, so phases diverge over time: restart periodically
+// if alignment matters over hours.
+func StartPeriodicSynchronized(targets []PollTarget, period PeriodicMeasure,
+	precision MeasureRepeatability) ([]time.Duration, error) {
+
+	offsets := make([]time.Duration, len(targets))
+	var first time.Time
+	for i, item := range targets {
+		cmd := item.Sensor.getPeriodicMeasurementCommand(period, precision)
+		_, err := item.I2C.WriteBytes(cmd)
+		if err != nil {
+			return nil, wrapBusError(item.I2C, cmd, err)
+		}
+		now := time.Now()
+		if i == 0 {
+			first = now
+		}
+		offsets[i] = now.Sub(first)
+		item.Sensor.setLastCmd(cmd)
+		item.Sensor.setPeriodic(period, precision)
+	}
+	return offsets, nil
+}
+
+// FetchSynchronized fetch results of "periodic data acquisition mode"
+// from all sensors started with StartPeriodicSynchronized one after another,
+// so samples are obtained nearly simultaneously. Result slice has
+// the same order as targets, with error specified for each sensor.
+func FetchSynchronized(ctx context.Context, targets []PollTarget) []PollResult {
+	results := make([]PollResult, len(targets))
+	for i, item := range targets {
+		ut, urh, err := item.Sensor.FetchUncompTemperatureAndHumidityWithContext(ctx, item.I2C)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Measurement = item.Sensor.newMeasurement(ut, urh)
+	}
+	return results
+}
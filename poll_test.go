@@ -0,0 +1,84 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"bytes"
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestPollAllResetDetected(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	bus.SetResponse(sht3x.CMD_READ_STATUS_REG, sht3xtest.Words(uint16(sht3x.RESET_DETECTED)))
+	sensor := sht3x.NewSHT3X()
+	sensor.SetRestoreAlertsOnReset(true)
+	err := sensor.ApplyAlertConfig(bus, sht3x.AlertConfig{
+		HighSet:   sht3x.AlertLimit{Temperature: 60, RelativeHumidity: 80},
+		HighClear: sht3x.AlertLimit{Temperature: 58, RelativeHumidity: 79},
+		LowClear:  sht3x.AlertLimit{Temperature: -9, RelativeHumidity: 22},
+		LowSet:    sht3x.AlertLimit{Temperature: -10, RelativeHumidity: 20},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Writes = nil
+
+	results := sht3x.PollAll([]sht3x.PollTarget{{Sensor: sensor, I2C: bus}},
+		sht3x.RepeatabilityLow)
+	if err := results[0].Err; err != nil {
+		t.Fatal(err)
+	}
+	// measurement, status register read, 4 alert limits and status clear
+	if len(bus.Writes) != 7 {
+		t.Fatalf("got bus writes %X, want 7", bus.Writes)
+	}
+	if !bytes.Equal(bus.Writes[6], sht3x.CMD_CLEAR_STATUS_REG) {
+		t.Errorf("last write %X, want status register clear", bus.Writes[6])
+	}
+	stats := sensor.TimingStats()
+	if stats.Writes != 1 || stats.Waits != 1 {
+		t.Errorf("got %d writes, %d waits in timing stats, want 1, 1",
+			stats.Writes, stats.Waits)
+	}
+}
+
+func TestPollAllStuck(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	bus.SetResponse(sht3x.CMD_READ_STATUS_REG, sht3xtest.Words(0x0000))
+	sensor := sht3x.NewSHT3X()
+	sensor.SetStuckDetection(2)
+	targets := []sht3x.PollTarget{{Sensor: sensor, I2C: bus}}
+
+	results := sht3x.PollAll(targets, sht3x.RepeatabilityLow)
+	if err := results[0].Err; err != nil {
+		t.Fatal(err)
+	}
+	results = sht3x.PollAll(targets, sht3x.RepeatabilityLow)
+	if err := results[0].Err; err != sht3x.ErrSensorStuck {
+		t.Errorf("got error %v, want ErrSensorStuck", err)
+	}
+}
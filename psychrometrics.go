@@ -0,0 +1,169 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"errors"
+	"math"
+)
+
+// Magnus formula coefficients for saturation vapor pressure over water
+// (Sonntag 1990, as recommended in Sensirion application note
+// "Introduction to Humidity"), valid in range -45..60*C:
+// Es(T) = 6.112 hPa * exp(17.62 * T / (243.12 + T)).
+const (
+	magnusE0 = 0.6112 // kPa
+	magnusB  = 17.62
+	magnusC  = 243.12 // Celsius
+)
+
+// SaturationVaporPressure return saturation water vapor pressure
+// in kPa at temperature specified in Celsius.
+func SaturationVaporPressure(tempC float32) float32 {
+	t := float64(tempC)
+	es := magnusE0 * math.Exp(magnusB*t/(magnusC+t))
+	return float32(es)
+}
+
+// VPD return vapor pressure deficit in kPa, which is difference between
+// saturation and actual water vapor pressure at temperature specified
+// in Celsius and relative humidity in percent.
+func VPD(tempC, rh float32) float32 {
+	es := SaturationVaporPressure(tempC)
+	vpd := es * (1 - rh/100)
+	return round32(vpd, 3)
+}
+
+// Lowest relative humidity used in dew point calculation,
+// which avoid logarithm of zero for completely dry air.
+const dewPointMinHumidity = 0.01 // Percent
+
+// DewPoint return dew point in Celsius at temperature specified in Celsius
+// and relative humidity in percent, using Magnus formula (Magnus-Tetens form
+// with Sonntag 1990 coefficients). Humidity below 0.01% (including zero)
+// is treated as 0.01%, so result is always finite. Below freezing result
+// is dew point over supercooled water, rather than frost point.
+func DewPoint(tempC, relHumidity float32) float32 {
+	rh := float64(relHumidity)
+	if rh < dewPointMinHumidity {
+		rh = dewPointMinHumidity
+	} else if rh > 100 {
+		rh = 100
+	}
+	t := float64(tempC)
+	gamma := math.Log(rh/100) + magnusB*t/(magnusC+t)
+	td := magnusC * gamma / (magnusB - gamma)
+	return round32(float32(td), 2)
+}
+
+// DewPointFromRH return dew point in Celsius at temperature specified
+// in Celsius and relative humidity in percent. It's the same as DewPoint,
+// named to pair with RHFromDewPoint.
+func DewPointFromRH(tempC, rh float32) float32 {
+	// Reroute call
+	return DewPoint(tempC, rh)
+}
+
+// ReadTemperatureHumidityAndDewPoint returns temperature, relative humidity
+// obtained from sensor in "single shot mode" and dew point calculated from them.
+func (v *SHT3X) ReadTemperatureHumidityAndDewPoint(i2c I2CBus,
+	precision MeasureRepeatability) (temp, rh, dewPoint float32, err error) {
+
+	temp, rh, err = v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return temp, rh, DewPoint(temp, rh), nil
+}
+
+// RHFromDewPoint return relative humidity in percent, which correspond
+// to dew point at temperature specified (both in Celsius), using
+// Magnus formula. This is reverse of DewPointFromRH, which let control loops
+// work with dew point targets. Result is limited to 100%, since dew point
+// above temperature means condensation.
+func RHFromDewPoint(tempC, dewPointC float32) float32 {
+	t := float64(tempC)
+	td := float64(dewPointC)
+	rh := 100 * math.Exp(magnusB*td/(magnusC+td)-magnusB*t/(magnusC+t))
+	if rh > 100 {
+		rh = 100
+	}
+	return round32(float32(rh), 2)
+}
+
+// Specific gas constant for water vapor and offset between Celsius
+// and Kelvin scales, used in absolute humidity calculation.
+const (
+	waterVaporGasConstant = 461.5 // J/(kg*K)
+	celsiusToKelvin       = 273.15
+)
+
+// AbsoluteHumidity return absolute humidity in g/m3 at temperature specified
+// in Celsius and relative humidity in percent. Actual vapor pressure
+// e = RH/100 * Es(T) (see SaturationVaporPressure) is converted
+// to vapor density with ideal gas law: AH = e / (Rv * T), where
+// Rv = 461.5 J/(kg*K) and T is temperature in Kelvin.
+func AbsoluteHumidity(tempC, relHumidity float32) float32 {
+	e := float64(SaturationVaporPressure(tempC)) * 1000 * float64(relHumidity) / 100 // Pa
+	ah := e / (waterVaporGasConstant * (float64(tempC) + celsiusToKelvin)) * 1000    // g/m3
+	return round32(float32(ah), 2)
+}
+
+// ReadTemperatureHumidityAndAbsoluteHumidity returns temperature, relative
+// humidity obtained from sensor in "single shot mode" and absolute humidity
+// in g/m3 calculated from them.
+func (v *SHT3X) ReadTemperatureHumidityAndAbsoluteHumidity(i2c I2CBus,
+	precision MeasureRepeatability) (temp, rh, absHumidity float32, err error) {
+
+	temp, rh, err = v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return temp, rh, AbsoluteHumidity(temp, rh), nil
+}
+
+// ReadRHAtReferencePressure measure relative humidity in "single shot mode"
+// and recalculate it to reference pressure, specified together with ambient
+// pressure in hPa. This is opt-in normalization for high-altitude or
+// pressurized deployments, which is not a property of the sensor itself.
+// Model assume air sample is compressed or expanded isothermally without
+// gain or loss of water vapor (constant mixing ratio), so vapor partial
+// pressure, and hence RH, scale proportionally to total pressure:
+// RHref = RH * refHPa / ambientHPa. Enhancement factor and non-ideal gas
+// behavior are ignored (error well below sensor accuracy at 300..1100 hPa).
+// Result is limited to 100%, since excess vapor would condense.
+func (v *SHT3X) ReadRHAtReferencePressure(i2c I2CBus, precision MeasureRepeatability,
+	ambientHPa, refHPa float32) (float32, error) {
+
+	if ambientHPa <= 0 || refHPa <= 0 {
+		return 0, errors.New("Pressure must be positive")
+	}
+	_, rh, err := v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, err
+	}
+	rhRef := float64(rh) * float64(refHPa) / float64(ambientHPa)
+	if rhRef > 100 {
+		rhRef = 100
+	}
+	return round32(float32(rhRef), 2), nil
+}
@@ -0,0 +1,99 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import "sync"
+
+// ReferenceOffset wrap sensor to make reported temperature track external
+// reference sensor, which is useful in enclosures, where SHT3x reads slightly
+// warm due to nearby electronics. On each read reference is queried via
+// Source callback and difference between reference and sensor temperature
+// is smoothed with exponential moving average (Smoothing factor in range
+// (0..1], where 1 means no smoothing), so reference noise doesn't pass
+// through. Resulting dynamic offset is added to sensor temperature.
+// Relative humidity is reported as is: it remains relative to sensor temperature.
+// ReferenceOffset is safe for concurrent use, as long as exported fields
+// are not changed, once it's shared between goroutines.
+type ReferenceOffset struct {
+	sensor    *SHT3X
+	Source    func() (float32, error)
+	Smoothing float32
+	// mu guard fields below
+	mu     sync.Mutex
+	offset float32
+	valid  bool
+}
+
+// NewReferenceOffset return new reference offset wrapper with no offset
+// established yet.
+func NewReferenceOffset(sensor *SHT3X, source func() (float32, error),
+	smoothing float32) *ReferenceOffset {
+
+	v := &ReferenceOffset{sensor: sensor, Source: source, Smoothing: smoothing}
+	return v
+}
+
+// Offset return current temperature offset, and false if no reference
+// reading was obtained yet.
+func (v *ReferenceOffset) Offset() (float32, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.offset, v.valid
+}
+
+// update recalculate offset from new pair of reference and sensor temperature.
+// Must be called with mu locked.
+func (v *ReferenceOffset) update(ref, temp float32) {
+	diff := ref - temp
+	if !v.valid || v.Smoothing <= 0 || v.Smoothing >= 1 {
+		v.offset = diff
+	} else {
+		v.offset += v.Smoothing * (diff - v.offset)
+	}
+	v.valid = true
+}
+
+// ReadMeasurement make measurement in "single shot mode" and apply
+// dynamic offset to temperature. If reference is unavailable,
+// error is logged and last known offset is applied.
+func (v *ReferenceOffset) ReadMeasurement(i2c I2CBus,
+	precision MeasureRepeatability) (Measurement, error) {
+
+	m, err := v.sensor.ReadMeasurement(i2c, precision)
+	if err != nil {
+		return Measurement{}, err
+	}
+	ref, err := v.Source()
+	v.mu.Lock()
+	if err == nil {
+		v.update(ref, m.Temperature)
+	}
+	offset := v.offset
+	v.mu.Unlock()
+	if err != nil {
+		lg.Warnf("Reference temperature is unavailable: %v", err)
+	} else {
+		lg.Debugf("Reference temperature = %v*C, offset = %v*C", ref, offset)
+	}
+	m.Temperature = round32(m.Temperature+offset, 2)
+	return m, nil
+}
@@ -0,0 +1,66 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"sync"
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+// Run with "go test -race" to verify wrapper shared between
+// goroutines doesn't race on offset.
+func TestReferenceOffsetConcurrent(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	source := func() (float32, error) { return 23, nil }
+	ref := sht3x.NewReferenceOffset(sensor, source, 0.5)
+
+	const goroutines, reads = 4, 5
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < reads; j++ {
+				err := sensor.WithBusLock(func() error {
+					m, err := ref.ReadMeasurement(bus, sht3x.RepeatabilityLow)
+					if err == nil && m.Temperature != 23 {
+						t.Errorf("temperature = %v*C, want 23*C", m.Temperature)
+					}
+					return err
+				})
+				if err != nil {
+					t.Error(err)
+				}
+				ref.Offset()
+			}
+		}()
+	}
+	wg.Wait()
+	if offset, ok := ref.Offset(); !ok || offset != -2 {
+		t.Errorf("offset = %v, %v, want -2, true", offset, ok)
+	}
+}
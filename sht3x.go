@@ -24,14 +24,16 @@ package sht3x
 import (
 	"bytes"
 	"context"
-	"encoding/binary"
 	"errors"
+	"fmt"
+	"math"
 	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	i2c "github.com/d2r2/go-i2c"
 	shell "github.com/d2r2/go-shell"
 	"github.com/davecgh/go-spew/spew"
 )
@@ -88,8 +90,16 @@ var (
 	CMD_ART          = []byte{0x2B, 0x32} // Activate "accelerated response time"
 	CMD_BREAK        = []byte{0x30, 0x93} // Interrupt "periodic acqusition mode" and return to "single shot mode"
 	CMD_RESET        = []byte{0x30, 0xA2} // Soft reset command
+	CMD_READ_SERIAL  = []byte{0x37, 0x80} // Read unique serial number
+
+	// General call commands, sent to GeneralCallAddress.
+	CMD_GENERAL_CALL_RESET = []byte{0x06} // Reset all devices on the bus
 )
 
+// GeneralCallAddress is i2c-bus "general call" address,
+// which all devices on the bus listen to.
+const GeneralCallAddress = 0x00
+
 // MeasureRepeatability used to define measure precision.
 type MeasureRepeatability int
 
@@ -114,7 +124,8 @@ func (v MeasureRepeatability) String() string {
 }
 
 // GetMeasureTime define how long to wait for the measure process
-// to complete according to specification.
+// to complete according to specification: values are maximum
+// measurement durations from datasheet "System timing specifications".
 func (v MeasureRepeatability) GetMeasureTime() time.Duration {
 	switch v {
 	case RepeatabilityLow:
@@ -128,6 +139,52 @@ func (v MeasureRepeatability) GetMeasureTime() time.Duration {
 	}
 }
 
+// EffectiveResolution return typical repeatability (noise level)
+// of temperature (Celsius) and relative humidity (percent)
+// measurements according to specification (tables 1 and 2).
+func (v MeasureRepeatability) EffectiveResolution() (tempC, rhPct float32) {
+	switch v {
+	case RepeatabilityLow:
+		return 0.15, 0.21
+	case RepeatabilityMedium:
+		return 0.08, 0.15
+	case RepeatabilityHigh:
+		return 0.04, 0.08
+	default:
+		return 0, 0
+	}
+}
+
+// Estimated time of bus transfers in single measurement (command write
+// and 6 bytes read at 100 kHz) plus driver calls overhead.
+const singleShotBusOverhead = time.Millisecond
+
+// MaxSingleShotRate return maximum safe rate (reads per second) of back-to-back
+// measurements in "single shot mode" with repeatability specified: conversion
+// time plus bus transfers, with 2x safety margin, which leave sensor idle
+// half of the time to limit self-heating.
+func MaxSingleShotRate(precision MeasureRepeatability) float32 {
+	measure := precision.GetMeasureTime()
+	if measure == 0 {
+		return 0
+	}
+	period := 2 * (measure + singleShotBusOverhead)
+	return round32(float32(time.Second)/float32(period), 1)
+}
+
+// GetReadDuration return expected time "single shot mode" read call
+// (ReadTemperatureAndRelativeHumidity and alike) block for: conversion
+// time from GetMeasureTime plus estimated bus transfers overhead
+// (singleShotBusOverhead). Use it to budget real-time loops before call.
+// Bus contention and retries are not included.
+func (v MeasureRepeatability) GetReadDuration() time.Duration {
+	measure := v.GetMeasureTime()
+	if measure == 0 {
+		return 0
+	}
+	return measure + singleShotBusOverhead
+}
+
 // StatusRegFlag determine sensor states.
 // It shows various sensor pending events and returns heater status.
 type StatusRegFlag uint16
@@ -173,6 +230,33 @@ func (v StatusRegFlag) String() string {
 	return buf.String()
 }
 
+// ParseStatusRegFlag restore flags from the string produced by String() call,
+// for instance: "ALERT_PENDING | HEATER_ENABLED". Empty string means no flags set.
+func ParseStatusRegFlag(s string) (StatusRegFlag, error) {
+	flags := map[string]StatusRegFlag{
+		"ALERT_PENDING":         ALERT_PENDING,
+		"HEATER_ENABLED":        HEATER_ENABLED,
+		"HUMIDITY_ALERT":        HUMIDITY_ALERT,
+		"TEMPERATURE_ALERT":     TEMPERATURE_ALERT,
+		"RESET_DETECTED":        RESET_DETECTED,
+		"COMMAND_FAILED":        COMMAND_FAILED,
+		"WRITE_DATA_CRC_FAILED": WRITE_DATA_CRC_FAILED,
+	}
+	var v StatusRegFlag
+	if strings.TrimSpace(s) == "" {
+		return v, nil
+	}
+	for _, token := range strings.Split(s, "|") {
+		token = strings.TrimSpace(token)
+		flag, ok := flags[token]
+		if !ok {
+			return 0, errors.New(spew.Sprintf("Unknown status register flag %q", token))
+		}
+		v |= flag
+	}
+	return v, nil
+}
+
 // PeriodicMeasure identify pause between subsequent measures
 // in "periodic data acquisition" mode.
 type PeriodicMeasure int
@@ -203,7 +287,8 @@ func (v PeriodicMeasure) String() string {
 	}
 }
 
-// GetWaitDuration identify pause between measures depending on PeriodicMeasure value.
+// GetWaitDuration identify pause between measures depending on PeriodicMeasure value,
+// which is derived from measurement rate encoded in periodic mode start command.
 func (v PeriodicMeasure) GetWaitDuration() time.Duration {
 	var timeDur time.Duration
 	switch v {
@@ -221,86 +306,487 @@ func (v PeriodicMeasure) GetWaitDuration() time.Duration {
 	return timeDur
 }
 
+// getRetryDuration return pause between fetch retries made after the first
+// one, which already waited full period. Remaining retries are spread evenly
+// across one more period (instead of fixed 1/10 of period), so at slow rates
+// retries are not wasted in quick succession (400 ms rather than 200 ms
+// for 0.5 MPS), while next measurement is still caught in time.
+func (v PeriodicMeasure) getRetryDuration() time.Duration {
+	return v.GetWaitDuration() / fetchRetryCount
+}
+
+// getFetchRetryPause return pause after failed fetch attempt (starting from 1):
+// the first retry happens after full period, when next measurement
+// is surely ready, each of the rest after retryInterval.
+func (v PeriodicMeasure) getFetchRetryPause(attempt int,
+	retryInterval time.Duration) time.Duration {
+
+	if attempt <= 1 {
+		return v.GetWaitDuration()
+	}
+	return retryInterval
+}
+
+// GetMaxFetchDuration return worst case time fetch call
+// (FetchTemperatureAndRelativeHumidity and alike) block for with default
+// retry behavior: first retry after full period from GetWaitDuration,
+// remaining ones after period divided by retries count, plus bus transfers
+// overhead of each attempt. Usually fetch complete much faster,
+// if it's called right after data is ready.
+func (v PeriodicMeasure) GetMaxFetchDuration() time.Duration {
+	period := v.GetWaitDuration()
+	if period == 0 {
+		return 0
+	}
+	return period + (fetchRetryCount-1)*v.getRetryDuration() +
+		(fetchRetryCount+1)*singleShotBusOverhead
+}
+
+// ConversionFormula define variant of formula used to convert
+// raw sensor values to Celsius and relative humidity.
+type ConversionFormula int
+
+const (
+	// Formula from SHT3x datasheet: T = -45 + 175 * St / (2^16 - 1),
+	// RH = 100 * Srh / (2^16 - 1). Default one.
+	FormulaDatasheet ConversionFormula = iota + 1
+	// Formula with 2^16 divisor: T = -45 + 175 * St / 2^16,
+	// RH = 100 * Srh / 2^16, which is used in integer arithmetic
+	// of Sensirion embedded drivers. Gives results lower than
+	// FormulaDatasheet up to 0.0027*C and 0.0015%RH at the top of range,
+	// which might change last digit after rounding.
+	FormulaPow2
+)
+
+// String define stringer interface.
+func (v ConversionFormula) String() string {
+	switch v {
+	case FormulaDatasheet:
+		return "Conversion Formula Datasheet"
+	case FormulaPow2:
+		return "Conversion Formula Pow2"
+	default:
+		return "<unknown>"
+	}
+}
+
+// divisor return denominator used in conversion formula.
+func (v ConversionFormula) divisor() float64 {
+	switch v {
+	case FormulaPow2:
+		return 0x10000
+	default:
+		return 0x10000 - 1
+	}
+}
+
+// I2CBus is i2c-bus connection to the sensor, used by all SHT3X methods.
+// It's implemented by *i2c.I2C from github.com/d2r2/go-i2c; fake
+// implementation for unit testing without hardware is provided
+// by sht3xtest package. Bus number and address are used
+// to give context to errors.
+type I2CBus interface {
+	WriteBytes(buf []byte) (int, error)
+	ReadBytes(buf []byte) (int, error)
+	GetBus() int
+	GetAddr() uint8
+}
+
 // SHT3X is a sensor itself.
+// All internal state (last command, status register cache, periodic mode
+// parameters, reset tracking, heater state, statistics and settings)
+// is guarded by mutex, so sensor could be shared between goroutines. Though, to keep bus communication of concurrent calls
+// from being interleaved (for instance, fetch and status register read),
+// wrap calls with WithBusLock.
 type SHT3X struct {
+	// mu guard all fields below
+	mu            sync.Mutex
 	lastStatusReg *uint16
 	lastCmd       []byte
 	lastPeriodic  PeriodicMeasure
 	lastPrecision MeasureRepeatability
+	busLock       sync.Locker
+	heaterOn      bool
+	heaterOffTime time.Time
+	heaterSettle  time.Duration
+	lastEmitted   *Measurement
+	formula       ConversionFormula
+	// duplicates suppression in periodic mode iterator
+	suppressDuplicates bool
+	duplicates         int
+	// reset tracking
+	resetTime      time.Time
+	resetFlagClear bool
+	clock          func() time.Time
+	timing         TimingStats
+	includeRaw     bool
+	heaterPolicy   HeaterHumidityPolicy
+	// stuck sensor detection
+	stuckLimit              int
+	stuckCount              int
+	stuckLastT, stuckLastRH uint16
+	measurementTimeout      time.Duration
+	// alert configuration restore after reset
+	alertConfig   *AlertConfig
+	restoreAlerts bool
+	model         Model
+	// samples with bad CRC skipped by fetch
+	crcSkipped int
+	// calibration offsets added to converted values
+	tempOffset float32
+	rhOffset   float32
 }
 
+// DefaultHeaterSettleWindow define how long after heater switched off
+// measurements are suspected to be biased by residual heat.
+const DefaultHeaterSettleWindow = 30 * time.Second
+
 // NewSHT3X return new sensor instance.
 func NewSHT3X() *SHT3X {
-	v := &SHT3X{}
+	v := &SHT3X{busLock: &sync.Mutex{},
+		heaterSettle: DefaultHeaterSettleWindow,
+		formula:      FormulaDatasheet,
+		clock:        time.Now,
+		heaterPolicy: HeaterHumidityFlag,
+		model:        ModelSHT3X}
 	return v
 }
 
+// SetClock define function used to obtain current time for measurement
+// timestamps and time-based decisions (heater settle window, reset tracking,
+// time windows of wrappers), which let tests control time.
+// Nil value restore default time.Now.
+func (v *SHT3X) SetClock(clock func() time.Time) {
+	if clock == nil {
+		clock = time.Now
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.clock = clock
+}
+
+// now return current time obtained from clock.
+func (v *SHT3X) now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.nowLocked()
+}
+
+// nowLocked do the same as now. Must be called with mu locked.
+func (v *SHT3X) nowLocked() time.Time {
+	if v.clock == nil {
+		return time.Now()
+	}
+	return v.clock()
+}
+
+// SetConversionFormula select formula variant used to convert raw values,
+// so results could match bit-to-bit specific reference implementation.
+// Default is FormulaDatasheet.
+func (v *SHT3X) SetConversionFormula(formula ConversionFormula) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.formula = formula
+}
+
+// SetCalibration define offsets added to temperature (Celsius)
+// and relative humidity (percent) converted from raw values,
+// to compensate for board self-heating and alike. Relative humidity
+// is limited to [0..100] range after offset applied. Offsets affect
+// all read and fetch methods, and alert limits as well: limits are
+// written and read back in calibrated values (except FactoryDefaults,
+// which write datasheet defaults as is). Default is zero offsets.
+func (v *SHT3X) SetCalibration(tempOffset, humidityOffset float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tempOffset = tempOffset
+	v.rhOffset = humidityOffset
+}
+
+// GetCalibration return temperature and relative humidity offsets
+// defined by SetCalibration.
+func (v *SHT3X) GetCalibration() (tempOffset, humidityOffset float32) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.tempOffset, v.rhOffset
+}
+
+// GetConversionFormula return formula variant used to convert raw values.
+func (v *SHT3X) GetConversionFormula() ConversionFormula {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.formula
+}
+
+// SetBusLocker define lock shared with other devices on the same i2c-bus,
+// which is acquired by WithBusLock. By default sensor use own private lock,
+// nil value restore this default.
+func (v *SHT3X) SetBusLocker(locker sync.Locker) {
+	if locker == nil {
+		locker = &sync.Mutex{}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.busLock = locker
+}
+
+// WithBusLock hold bus lock during execution of f, so multi-step operations
+// (for instance: start periodic measurement, fetch, break) are not interleaved
+// with communication of other devices, which share the same lock.
+// Lock is not reentrant: don't call WithBusLock from inside of f.
+func (v *SHT3X) WithBusLock(f func() error) error {
+	v.mu.Lock()
+	locker := v.busLock
+	v.mu.Unlock()
+	locker.Lock()
+	defer locker.Unlock()
+	return f()
+}
+
+// setLastCmd remember last command sent to sensor.
+func (v *SHT3X) setLastCmd(cmd []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastCmd = cmd
+}
+
+// setPeriodic remember parameters of "periodic data acquisition mode" started.
+func (v *SHT3X) setPeriodic(period PeriodicMeasure, precision MeasureRepeatability) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastPeriodic = period
+	v.lastPrecision = precision
+}
+
+// getPeriodic return pace of "periodic data acquisition mode" in use.
+func (v *SHT3X) getPeriodic() PeriodicMeasure {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.lastPeriodic
+}
+
+// invalidateStatusReg drop cached status register value,
+// so next ReadStatusReg call read it from sensor.
+func (v *SHT3X) invalidateStatusReg() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastStatusReg = nil
+}
+
 // ReadStatusReg return status register flags.
 // You should use constants of type StatusRegFlag to distinguish
 // individual states received from sensor.
-func (v *SHT3X) ReadStatusReg(i2c *i2c.I2C) (uint16, error) {
-	if v.lastStatusReg == nil {
-		_, err := i2c.WriteBytes(CMD_READ_STATUS_REG)
-		if err != nil {
-			return 0, err
-		}
-		reg, err := v.readDataWithCRCCheck(i2c, 1)
-		if err != nil {
-			return 0, err
+// Value is cached: it's read from sensor only if cache is empty, and cache
+// is dropped by commands changing status (Reset, ClearStatusReg,
+// SetHeaterStatus) and by
+// Get.../Check... status methods, which always read fresh value.
+// Use ReadStatusRegFresh to bypass cache explicitly.
+func (v *SHT3X) ReadStatusReg(i2c I2CBus) (uint16, error) {
+	v.mu.Lock()
+	cached := v.lastStatusReg
+	v.mu.Unlock()
+	if cached != nil {
+		return *cached, nil
+	}
+	_, err := i2c.WriteBytes(CMD_READ_STATUS_REG)
+	if err != nil {
+		return 0, wrapBusError(i2c, CMD_READ_STATUS_REG, err)
+	}
+	reg, err := v.readDataWithCRCCheck(i2c, 1)
+	if err != nil {
+		return 0, wrapBusError(i2c, CMD_READ_STATUS_REG, err)
+	}
+	err = v.updateStatusReg(i2c, reg[0])
+	if err != nil {
+		return 0, err
+	}
+	return reg[0], nil
+}
+
+// updateStatusReg process status register just read from sensor:
+// update cache, track sensor reset and restore alert configuration,
+// if RESET_DETECTED flag is set.
+func (v *SHT3X) updateStatusReg(i2c I2CBus, reg uint16) error {
+	v.mu.Lock()
+	v.lastStatusReg = &reg
+	v.trackReset(reg)
+	v.mu.Unlock()
+	if StatusRegFlag(reg)&RESET_DETECTED != 0 {
+		return v.restoreAlertConfig(i2c)
+	}
+	return nil
+}
+
+// ReadStatusRegFresh return status register flags, always read
+// from sensor, rather than cached value, and update cache.
+func (v *SHT3X) ReadStatusRegFresh(i2c I2CBus) (uint16, error) {
+	v.invalidateStatusReg()
+	return v.ReadStatusReg(i2c)
+}
+
+// ClearStatusReg clear all alert flags and reset detected flag in status register.
+// Clearing RESET_DETECTED flag is required to detect next sensor reset.
+func (v *SHT3X) ClearStatusReg(i2c I2CBus) error {
+	lg.Debug("Clearing status register...")
+	cmd := CMD_CLEAR_STATUS_REG
+	_, err := i2c.WriteBytes(cmd)
+	if err != nil {
+		return wrapBusError(i2c, cmd, err)
+	}
+	v.mu.Lock()
+	v.lastCmd = cmd
+	v.lastStatusReg = nil
+	v.resetFlagClear = true
+	v.mu.Unlock()
+	// No conversion time defined in docs for this command,
+	// but error thrown out, if no any pause provided.
+	time.Sleep(time.Millisecond * 1)
+	return nil
+}
+
+// trackReset update reset time estimation from status register value.
+// Once RESET_DETECTED flag observed after it was clear,
+// sensor was reset somewhere between these two observations.
+// Must be called with mu locked.
+func (v *SHT3X) trackReset(reg uint16) {
+	if StatusRegFlag(reg)&RESET_DETECTED != 0 {
+		if v.resetFlagClear {
+			v.resetTime = v.nowLocked()
+			v.resetFlagClear = false
 		}
-		v.lastStatusReg = &reg[0]
+	} else {
+		v.resetFlagClear = true
+	}
+}
+
+// TimeSinceReset return estimation of time elapsed since last sensor reset,
+// either initiated by Reset call, or detected via RESET_DETECTED flag
+// of status register, which appeared after being clear (use ClearStatusReg
+// to clear it). Detected reset time is the moment of observation, so estimation
+// is the lower bound of real uptime, which accuracy depends on how often
+// status register is read. Second value is false, if no reset was observed yet.
+func (v *SHT3X) TimeSinceReset() (time.Duration, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.resetTime.IsZero() {
+		return 0, false
 	}
-	return *v.lastStatusReg, nil
+	return v.nowLocked().Sub(v.resetTime), true
 }
 
 // readDataWithCRCCheck read block of data which ordinary contain
 // uncompensated temperature and humidity values.
-func (v *SHT3X) readDataWithCRCCheck(i2c *i2c.I2C, blockCount int) ([]uint16, error) {
-	const blockSize = 2 + 1
-	data := make([]struct {
-		Data [2]byte
-		CRC  byte
-	}, blockCount)
-
-	err := readDataToStruct(i2c, blockSize*blockCount, binary.BigEndian, data)
+func (v *SHT3X) readDataWithCRCCheck(i2c I2CBus, blockCount int) ([]uint16, error) {
+	buf := make([]byte, crcBlockSize*blockCount)
+	results := make([]uint16, blockCount)
+	err := v.readDataWithCRCCheckToBuf(i2c, buf, results)
 	if err != nil {
 		return nil, err
 	}
-	var results []uint16
-	for i := 0; i < blockCount; i++ {
-		calcCRC := calcCRC_SHT3X(0xFF, data[i].Data[:2])
-		crc := data[i].CRC
+	return results, nil
+}
+
+// ErrCRCMismatch returned, when CRC of data received from sensor
+// doesn't match calculated one, which signal data corruption on the bus.
+var ErrCRCMismatch = errors.New("CRCs doesn't match")
+
+// CRCError describe CRC mismatch of data block received from sensor.
+// It match ErrCRCMismatch with errors.Is, and could be extracted
+// with errors.As to tell data corruption from bus errors.
+type CRCError struct {
+	Expected byte // CRC calculated from data received
+	Actual   byte // CRC received from sensor
+	Block    int  // Index of data block (2 bytes of data followed by CRC)
+}
+
+// Error implement error interface.
+func (v *CRCError) Error() string {
+	return spew.Sprintf("%v: CRC from sensor (0x%0X) != calculated CRC (0x%0X) in block %d",
+		ErrCRCMismatch, v.Actual, v.Expected, v.Block)
+}
+
+// Is allow errors.Is(err, ErrCRCMismatch) check.
+func (v *CRCError) Is(target error) bool {
+	return target == ErrCRCMismatch
+}
+
+// Size of data block: 2 bytes of data followed by CRC byte.
+const crcBlockSize = 2 + 1
+
+// readDataWithCRCCheckToBuf do the same as readDataWithCRCCheck, but use
+// buffers provided by caller to avoid memory allocation. Buf length must be
+// equal to crcBlockSize multiplied by results length.
+func (v *SHT3X) readDataWithCRCCheckToBuf(i2c I2CBus, buf []byte, results []uint16) error {
+	start := time.Now()
+	_, err := i2c.ReadBytes(buf)
+	dur := time.Since(start)
+	v.mu.Lock()
+	v.timing.addRead(dur)
+	v.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		block := buf[i*crcBlockSize : (i+1)*crcBlockSize]
+		calcCRC := calcCRC_SHT3X(0xFF, block[:2])
+		crc := block[2]
 		if calcCRC != crc {
-			err := errors.New(spew.Sprintf(
-				"CRCs doesn't match: CRC from sensor (0x%0X) != calculated CRC (0x%0X)",
-				crc, calcCRC))
-			return nil, err
+			return &CRCError{Expected: calcCRC, Actual: crc, Block: i}
 		} else {
 			lg.Debugf("CRCs verified: CRC from sensor (0x%0X) = calculated CRC (0x%0X)",
 				crc, calcCRC)
 		}
-		results = append(results, getU16BE(data[i].Data[:2]))
-
+		results[i] = getU16BE(block[:2])
 	}
-	return results, nil
+	return nil
 }
 
 // Reset reboot a sensor.
-func (v *SHT3X) Reset(i2c *i2c.I2C) error {
+func (v *SHT3X) Reset(i2c I2CBus) error {
 	lg.Debug("Reset sensor...")
 	cmd := CMD_RESET
 	_, err := i2c.WriteBytes(cmd)
 	if err != nil {
-		return err
+		return wrapBusError(i2c, cmd, err)
 	}
+	v.mu.Lock()
 	v.lastCmd = cmd
+	v.lastStatusReg = nil
+	v.resetTime = v.nowLocked()
+	v.resetFlagClear = false
+	v.mu.Unlock()
+	// Power-up time from specification
+	time.Sleep(time.Microsecond * 1500)
+	return nil
+}
+
+// ResetGeneralCall reboot all sensors on the bus simultaneously,
+// using i2c-bus "general call" reset. Unlike Reset, which reboot
+// a single sensor, it affects every SHT3x (and any other device, which
+// support general call) on the bus, so use it to bring whole cluster
+// to known state at start. Connection must be opened at
+// GeneralCallAddress, for instance with i2c.NewI2C(sht3x.GeneralCallAddress, 1).
+// SHT3X objects, created before, keep cached state, so call
+// ReadStatusRegFresh to update it.
+func ResetGeneralCall(i2c I2CBus) error {
+	lg.Debug("Reset all sensors on the bus with general call...")
+	if i2c.GetAddr() != GeneralCallAddress {
+		return errors.New(spew.Sprintf(
+			"General call reset must be sent to address 0x%02X, but connection uses 0x%02X",
+			GeneralCallAddress, i2c.GetAddr()))
+	}
+	cmd := CMD_GENERAL_CALL_RESET
+	_, err := i2c.WriteBytes(cmd)
+	if err != nil {
+		return wrapBusError(i2c, cmd, err)
+	}
 	// Power-up time from specification
 	time.Sleep(time.Microsecond * 1500)
 	return nil
 }
 
 // SetHeaterStatus enable or disable heater.
-func (v *SHT3X) SetHeaterStatus(i2c *i2c.I2C, enableHeater bool) error {
+func (v *SHT3X) SetHeaterStatus(i2c I2CBus, enableHeater bool) error {
 	lg.Debug("Setting heater on/off...")
 	var cmd []byte
 	if enableHeater {
@@ -310,20 +796,47 @@ func (v *SHT3X) SetHeaterStatus(i2c *i2c.I2C, enableHeater bool) error {
 	}
 	_, err := i2c.WriteBytes(cmd)
 	if err != nil {
-		return err
+		return wrapBusError(i2c, cmd, err)
 	}
-	v.lastCmd = cmd
+	v.setLastCmd(cmd)
+	// heater status changed
+	v.invalidateStatusReg()
+	v.mu.Lock()
+	if v.heaterOn && !enableHeater {
+		v.heaterOffTime = v.nowLocked()
+	}
+	v.heaterOn = enableHeater
+	v.mu.Unlock()
 	// No conversion time defined in docs for this command,
 	// but error thrown out, if no any pause provided.
 	time.Sleep(time.Millisecond * 1)
 	return nil
 }
 
+// SetHeaterSettleWindow define how long after heater switched off
+// measurements are flagged with HeaterBiasSuspected.
+// Default value is DefaultHeaterSettleWindow.
+func (v *SHT3X) SetHeaterSettleWindow(window time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.heaterSettle = window
+}
+
+// heaterBiasSuspected return true, if heater is on, or was switched off
+// recently, so measurement might be biased by heater.
+// Must be called with mu locked.
+func (v *SHT3X) heaterBiasSuspected() bool {
+	if v.heaterOn {
+		return true
+	}
+	return !v.heaterOffTime.IsZero() &&
+		v.nowLocked().Sub(v.heaterOffTime) < v.heaterSettle
+}
+
 // GetHeaterStatus return heater status: enabled (true) or disabled (false).
-func (v *SHT3X) GetHeaterStatus(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) GetHeaterStatus(i2c I2CBus) (bool, error) {
 	lg.Debug("Getting heater status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -331,10 +844,9 @@ func (v *SHT3X) GetHeaterStatus(i2c *i2c.I2C) (bool, error) {
 }
 
 // GetAlertPendingStatus return alert pending status: found (true) or not (false).
-func (v *SHT3X) GetAlertPendingStatus(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) GetAlertPendingStatus(i2c I2CBus) (bool, error) {
 	lg.Debug("Getting alert pending status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -342,10 +854,9 @@ func (v *SHT3X) GetAlertPendingStatus(i2c *i2c.I2C) (bool, error) {
 }
 
 // GetHumidityAlertStatus return humidity alert pending status: found (true) or not (false).
-func (v *SHT3X) GetHumidityAlertStatus(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) GetHumidityAlertStatus(i2c I2CBus) (bool, error) {
 	lg.Debug("Getting humidity alert status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -353,21 +864,32 @@ func (v *SHT3X) GetHumidityAlertStatus(i2c *i2c.I2C) (bool, error) {
 }
 
 // GetTemperatureAlertStatus return humidity alert pending status: found (true) or not (false).
-func (v *SHT3X) GetTemperatureAlertStatus(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) GetTemperatureAlertStatus(i2c I2CBus) (bool, error) {
 	lg.Debug("Getting temperature alert status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
 	return (StatusRegFlag)(ur)&TEMPERATURE_ALERT != 0, nil
 }
 
+// GetActiveAlerts return temperature and humidity alert pending statuses
+// taken from single status register snapshot, so simultaneous alerts
+// are reported consistently.
+func (v *SHT3X) GetActiveAlerts(i2c I2CBus) (tempAlert, humAlert bool, err error) {
+	lg.Debug("Getting temperature and humidity alert statuses...")
+	ur, err := v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return false, false, err
+	}
+	flags := StatusRegFlag(ur)
+	return flags&TEMPERATURE_ALERT != 0, flags&HUMIDITY_ALERT != 0, nil
+}
+
 // CheckResetDetected return system reset detected : found (true) or not (false).
-func (v *SHT3X) CheckResetDetected(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) CheckResetDetected(i2c I2CBus) (bool, error) {
 	lg.Debug("Checking system reset status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -375,10 +897,9 @@ func (v *SHT3X) CheckResetDetected(i2c *i2c.I2C) (bool, error) {
 }
 
 // CheckCommandFailed return last command status: failed (true) or not (false).
-func (v *SHT3X) CheckCommandFailed(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) CheckCommandFailed(i2c I2CBus) (bool, error) {
 	lg.Debug("Checking last command status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -386,10 +907,9 @@ func (v *SHT3X) CheckCommandFailed(i2c *i2c.I2C) (bool, error) {
 }
 
 // CheckWrittedChecksumIsIncorrect return last command status: not correct (true) correct (false).
-func (v *SHT3X) CheckWrittenChecksumIsIncorrect(i2c *i2c.I2C) (bool, error) {
+func (v *SHT3X) CheckWrittenChecksumIsIncorrect(i2c I2CBus) (bool, error) {
 	lg.Debug("Checking last written data checksum status...")
-	v.lastStatusReg = nil
-	ur, err := v.ReadStatusReg(i2c)
+	ur, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return false, err
 	}
@@ -397,42 +917,113 @@ func (v *SHT3X) CheckWrittenChecksumIsIncorrect(i2c *i2c.I2C) (bool, error) {
 }
 
 // initiateMeasure used to initiate temperature and humidity measurement process.
-func (v *SHT3X) initiateMeasure(i2c *i2c.I2C, cmd []byte,
-	precision MeasureRepeatability) error {
+// Pause define how long to wait for conversion to complete.
+func (v *SHT3X) initiateMeasure(i2c I2CBus, cmd []byte,
+	pause time.Duration) error {
+
+	// Reroute call
+	return v.initiateMeasureWithContext(context.Background(), i2c, cmd, pause)
+}
+
+// initiateMeasureWithContext do the same as initiateMeasure, but interrupt
+// waiting for conversion once context is done. If context deadline expire
+// before command write and conversion could complete, context.DeadlineExceeded
+// is returned without command being sent.
+func (v *SHT3X) initiateMeasureWithContext(ctx context.Context, i2c I2CBus,
+	cmd []byte, pause time.Duration) error {
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Check deadline before command is sent, since sensor busy
+	// with abandoned conversion might NACK next command
+	if deadline, ok := ctx.Deadline(); ok &&
+		time.Now().Add(singleShotBusOverhead+pause).After(deadline) {
+		return context.DeadlineExceeded
+	}
+	start := time.Now()
 	_, err := i2c.WriteBytes(cmd)
+	dur := time.Since(start)
+	v.mu.Lock()
+	v.timing.addWrite(dur)
+	v.mu.Unlock()
 	if err != nil {
-		return err
+		return wrapBusError(i2c, cmd, err)
 	}
-	v.lastCmd = cmd
+	v.setLastCmd(cmd)
 
 	// Wait according to conversion time specification
-	pause := precision.GetMeasureTime()
-	time.Sleep(pause)
+	start = time.Now()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(pause):
+	}
+	dur = time.Since(start)
+	v.mu.Lock()
+	v.timing.addWait(dur)
+	v.mu.Unlock()
 	return nil
 }
 
+// SetMeasurementTimeout define time limit for entire "single shot mode"
+// measurement sequence: command write, conversion wait and data read.
+// If limit is exceeded, or would be exceeded by command write and
+// conversion wait, read methods return context.DeadlineExceeded
+// (in the latter case, before measurement command is sent). Bus transfers themselves
+// can't be interrupted, so limit is checked between steps.
+// Zero value (default) means no limit.
+func (v *SHT3X) SetMeasurementTimeout(timeout time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.measurementTimeout = timeout
+}
+
 // ReadUncompTemperatureAndHumidity returns uncompensated humidity and
 // temperature obtained from sensor in "single shot mode".
-func (v *SHT3X) ReadUncompTemperatureAndHumidity(i2c *i2c.I2C,
+func (v *SHT3X) ReadUncompTemperatureAndHumidity(i2c I2CBus,
 	precision MeasureRepeatability) (uint16, uint16, error) {
 
+	// Reroute call
+	return v.ReadUncompTemperatureAndHumidityWithWait(i2c, precision,
+		precision.GetMeasureTime())
+}
+
+// ReadUncompTemperatureAndHumidityWithWait returns uncompensated humidity and
+// temperature obtained from sensor in "single shot mode".
+// Wait parameter override conversion time defined by specification,
+// which might be helpful for marginal sensors failing with default timing.
+func (v *SHT3X) ReadUncompTemperatureAndHumidityWithWait(i2c I2CBus,
+	precision MeasureRepeatability, wait time.Duration) (uint16, uint16, error) {
+
 	lg.Debug("Measuring temperature and humidity...")
-	var cmd []byte
-	switch precision {
-	case RepeatabilityLow:
-		cmd = CMD_SINGLE_MEASURE_LOW
-	case RepeatabilityMedium:
-		cmd = CMD_SINGLE_MEASURE_MEDIUM
-	case RepeatabilityHigh:
-		cmd = CMD_SINGLE_MEASURE_HIGH
+	ctx := context.Background()
+	v.mu.Lock()
+	timeout := v.measurementTimeout
+	v.mu.Unlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
-	err := v.initiateMeasure(i2c, cmd, precision)
+	cmd := getSingleMeasurementCommand(precision)
+	err := v.initiateMeasureWithContext(ctx, i2c, cmd, wait)
 	if err != nil {
 		return 0, 0, err
 	}
+	if err = ctx.Err(); err != nil {
+		return 0, 0, err
+	}
 
 	data, err := v.readDataWithCRCCheck(i2c, 2)
+	if err != nil {
+		return 0, 0, wrapBusError(i2c, cmd, err)
+	}
+	// read might be delayed by clock stretching or bus contention
+	if err = ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	err = v.checkStuck(data[0], data[1])
 	if err != nil {
 		return 0, 0, err
 	}
@@ -441,10 +1032,22 @@ func (v *SHT3X) ReadUncompTemperatureAndHumidity(i2c *i2c.I2C,
 
 // ReadTemperatureAndRelativeHumidity returns humidity and
 // temperature obtained from sensor in "single shot mode".
-func (v *SHT3X) ReadTemperatureAndRelativeHumidity(i2c *i2c.I2C,
+func (v *SHT3X) ReadTemperatureAndRelativeHumidity(i2c I2CBus,
 	precision MeasureRepeatability) (float32, float32, error) {
 
-	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	// Reroute call
+	return v.ReadTemperatureAndRelativeHumidityWithWait(i2c, precision,
+		precision.GetMeasureTime())
+}
+
+// ReadTemperatureAndRelativeHumidityWithWait returns humidity and
+// temperature obtained from sensor in "single shot mode".
+// Wait parameter override conversion time defined by specification,
+// which might be helpful for marginal sensors failing with default timing.
+func (v *SHT3X) ReadTemperatureAndRelativeHumidityWithWait(i2c I2CBus,
+	precision MeasureRepeatability, wait time.Duration) (float32, float32, error) {
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidityWithWait(i2c, precision, wait)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -455,31 +1058,87 @@ func (v *SHT3X) ReadTemperatureAndRelativeHumidity(i2c *i2c.I2C,
 }
 
 // Convert uncompensated humidity to relative humidity.
+// Calculation made in float64 to avoid float32 rounding errors,
+// which could shift result by 0.01 after rounding.
 func (v *SHT3X) uncompHumidityToRelativeHumidity(uh uint16) float32 {
-	rh := float32(uh) * 100 / (0x10000 - 1)
-	rh2 := round32(rh, 2)
+	v.mu.Lock()
+	formula, offset := v.formula, v.rhOffset
+	v.mu.Unlock()
+	rh := float64(uh)*100/formula.divisor() + float64(offset)
+	rh = math.Max(0, math.Min(100, rh))
+	rh2 := float32(round64(rh, 2))
 	return rh2
 }
 
 // Convert uncompensated temperature to Celsius value.
+// Calculation made in float64 to avoid float32 rounding errors,
+// which could shift result by 0.01 after rounding.
 func (v *SHT3X) uncompTemperatureToCelsius(ut uint16) float32 {
-	temp := float32(ut)*175/(0x10000-1) - 45
-	temp2 := round32(temp, 2)
+	v.mu.Lock()
+	formula, offset := v.formula, v.tempOffset
+	v.mu.Unlock()
+	temp := float64(ut)*175/formula.divisor() - 45 + float64(offset)
+	temp2 := float32(round64(temp, 2))
 	return temp2
 }
 
+// Convert uncompensated temperature and humidity to Celsius and relative
+// humidity the same way, but without calibration offsets applied,
+// so values match ones sensor compare alert limits with.
+func (v *SHT3X) uncompToUncalibrated(ut, uh uint16) (float32, float32) {
+	divisor := v.GetConversionFormula().divisor()
+	temp := float32(round64(float64(ut)*175/divisor-45, 2))
+	rh := float32(round64(float64(uh)*100/divisor, 2))
+	return temp, rh
+}
+
+// ConvertRaw convert raw (uncompensated) temperature and humidity values
+// to Celsius and relative humidity, exactly as sensor read methods do,
+// including rounding to 2 decimal places. FormulaDatasheet is used.
+// Helpful to produce expected values in tests without hardware.
+func ConvertRaw(rawT, rawH uint16) (tempC, rh float32) {
+	v := &SHT3X{formula: FormulaDatasheet}
+	return v.uncompTemperatureToCelsius(rawT), v.uncompHumidityToRelativeHumidity(rawH)
+}
+
 // Reverse conversion of relative humidity to uncompensated one.
+// Calibration offset is not applied.
 func (v *SHT3X) relativeHumidityToUncompHimidity(rh float32) uint16 {
-	uh := uint16(rh * (0x10000 - 1) / 100)
+	uh := clampU16(float64(rh) * v.GetConversionFormula().divisor() / 100)
 	return uh
 }
 
 // Reverse conversion of Celsius to uncompensated temperature.
+// Calibration offset is not applied.
 func (v *SHT3X) celsiusToUncompTemperature(celsius float32) uint16 {
-	ut := uint16((celsius + 45) * (0x10000 - 1) / 175)
+	ut := clampU16((float64(celsius) + 45) * v.GetConversionFormula().divisor() / 175)
 	return ut
 }
 
+// Encode alert limit to the word stored by sensor: 7 most significant bits
+// of uncompensated humidity followed by 9 most significant bits
+// of uncompensated temperature. Calibration offset is not applied.
+func (v *SHT3X) encodeAlertLimit(temp, hum float32) uint16 {
+	ut := v.celsiusToUncompTemperature(temp)
+	uh := v.relativeHumidityToUncompHimidity(hum)
+	return uh&0xFE00 | (ut & 0xFF80 >> 7)
+}
+
+// Select proper "single shot mode" measurement command
+// depending on MeasureRepeatability parameter.
+func getSingleMeasurementCommand(precision MeasureRepeatability) []byte {
+	var cmd []byte
+	switch precision {
+	case RepeatabilityLow:
+		cmd = CMD_SINGLE_MEASURE_LOW
+	case RepeatabilityMedium:
+		cmd = CMD_SINGLE_MEASURE_MEDIUM
+	case RepeatabilityHigh:
+		cmd = CMD_SINGLE_MEASURE_HIGH
+	}
+	return cmd
+}
+
 // Select proper periodic measurement command depending on
 // PeriodicMeasure and MeasureRepeatability parameters.
 func (v *SHT3X) getPeriodicMeasurementCommand(period PeriodicMeasure,
@@ -542,38 +1201,124 @@ func (v *SHT3X) getPeriodicMeasurementCommand(period PeriodicMeasure,
 // to start continuous measurement process of temperature and humidity
 // with the pace defined by period parameter. Measurement process should be
 // interrupted by Break command. Use Fetch... methods to read results.
-func (v *SHT3X) StartPeriodicTemperatureAndHumidityMeasure(i2c *i2c.I2C,
+func (v *SHT3X) StartPeriodicTemperatureAndHumidityMeasure(i2c I2CBus,
 	period PeriodicMeasure, precision MeasureRepeatability) error {
 
 	cmd := v.getPeriodicMeasurementCommand(period, precision)
-	err := v.initiateMeasure(i2c, cmd, precision)
+	err := v.initiateMeasure(i2c, cmd, precision.GetMeasureTime())
 	if err != nil {
 		return err
 	}
-	v.lastPeriodic = period
-	v.lastPrecision = precision
+	v.setPeriodic(period, precision)
 
 	return nil
 }
 
 // Break interrupt "periodic data acquisition mode" and
 // return sensor to "single shot mode".
-func (v *SHT3X) Break(i2c *i2c.I2C) error {
+func (v *SHT3X) Break(i2c I2CBus) error {
 	lg.Debug("Interrupt periodic data acquisition mode...")
 	cmd := CMD_BREAK
 	_, err := i2c.WriteBytes(cmd)
+	if err != nil {
+		return wrapBusError(i2c, cmd, err)
+	}
+	v.setLastCmd(cmd)
+	return nil
+}
+
+// Time sensor need to abort ongoing measurement and enter
+// "single shot mode" after break command (datasheet, "Break command").
+const breakSettleTime = time.Millisecond
+
+// BreakAndWait interrupt "periodic data acquisition mode" as Break do,
+// then wait settle time specified by datasheet (1 ms), so next command
+// isn't rejected. Waiting could be interrupted with context cancellation.
+// If confirm is true, status register is read afterwards, and error
+// is returned, if sensor report COMMAND_FAILED, i.e. break wasn't processed.
+// Sensor doesn't expose current mode, so that's the best confirmation
+// available without waiting for periodic data (see DetectPeriodicMode).
+func (v *SHT3X) BreakAndWait(ctx context.Context, i2c I2CBus, confirm bool) error {
+	err := v.Break(i2c)
+	if err != nil {
+		return err
+	}
+	select {
+	// check for termination request
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(breakSettleTime):
+	}
+	if !confirm {
+		return nil
+	}
+	reg, err := v.ReadStatusRegFresh(i2c)
 	if err != nil {
 		return err
 	}
+	if StatusRegFlag(reg)&COMMAND_FAILED != 0 {
+		return errors.New(spew.Sprintf("Break command failed, status register = %v",
+			StatusRegFlag(reg)))
+	}
+	return nil
+}
+
+// periodicActive return true, if last command sent to sensor started
+// "periodic data acquisition mode", or activated ART.
+func (v *SHT3X) periodicActive() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if bytes.Equal(v.lastCmd, CMD_ART) {
+		return true
+	}
+	cmd := v.getPeriodicMeasurementCommand(v.lastPeriodic, v.lastPrecision)
+	return cmd != nil && reflect.DeepEqual(cmd, v.lastCmd)
+}
+
+// EnableART activate "accelerated response time" feature, which is
+// available in "periodic data acquisition mode" only: sensor switch
+// to 4 measurements per second, so fast transients are tracked.
+// Use Fetch... methods to read results, as before; Break return sensor
+// to "single shot mode".
+func (v *SHT3X) EnableART(i2c I2CBus) error {
+	if !v.periodicActive() {
+		return errors.New("Can't activate ART, since periodic data acquisition mode is not started")
+	}
+	lg.Debug("Activate accelerated response time...")
+	cmd := CMD_ART
+	_, err := i2c.WriteBytes(cmd)
+	if err != nil {
+		return wrapBusError(i2c, cmd, err)
+	}
+	v.mu.Lock()
 	v.lastCmd = cmd
+	v.lastPeriodic = Periodic4MPS
+	v.mu.Unlock()
+	// No conversion time defined in docs for this command,
+	// but error thrown out, if no any pause provided.
+	time.Sleep(time.Millisecond * 1)
 	return nil
 }
 
+// Number of attempts to read data in "periodic data acquisition mode"
+// after the first one failed.
+const fetchRetryCount = 5
+
+// defaultFetchTimeout return timeout used by Fetch... methods without
+// context parameter: pause between periodic measures multiplied
+// by number of read attempts, with 2x safety margin.
+func (v *SHT3X) defaultFetchTimeout() time.Duration {
+	return v.getPeriodic().GetWaitDuration() * (fetchRetryCount + 1) * 2
+}
+
 // FetchUncompTemperatureAndHumidity return
 // uncompensated temperature and humidity obtained from sensor.
-func (v *SHT3X) FetchUncompTemperatureAndHumidity(i2c *i2c.I2C) (ut uint16, uh uint16, err error) {
-	// Create default context
-	ctx := context.Background()
+// Call is limited by default timeout, which is 12 periods
+// of "periodic data acquisition mode" (24 sec for 0.5 MPS).
+func (v *SHT3X) FetchUncompTemperatureAndHumidity(i2c I2CBus) (ut uint16, uh uint16, err error) {
+	// Create default context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), v.defaultFetchTimeout())
+	defer cancel()
 	// Reroute call
 	return v.FetchUncompTemperatureAndHumidityWithContext(ctx,
 		i2c)
@@ -583,16 +1328,66 @@ func (v *SHT3X) FetchUncompTemperatureAndHumidity(i2c *i2c.I2C) (ut uint16, uh u
 // uncompensated temperature and humidity obtained from sensor.
 // Use context parameter, since operation is time consuming
 // (can take up to 2 seconds, waiting for results).
+// If context has a deadline, which expires before next retry
+// attempt, method returns context.DeadlineExceeded immediately,
+// rather than consuming the rest of retry attempts.
 func (v *SHT3X) FetchUncompTemperatureAndHumidityWithContext(parent context.Context,
-	i2c *i2c.I2C) (ut uint16, uh uint16, err error) {
+	i2c I2CBus) (ut uint16, uh uint16, err error) {
 
-	cmd := v.getPeriodicMeasurementCommand(v.lastPeriodic, v.lastPrecision)
-	if cmd == nil || !reflect.DeepEqual(cmd, v.lastCmd) {
+	// Reroute call
+	return v.FetchUncompTemperatureAndHumidityWithOptions(parent, i2c, nil)
+}
+
+// FetchOptions define retry behavior of fetching results
+// in "periodic data acquisition mode".
+type FetchOptions struct {
+	// Number of read attempts after the first one failed.
+	// Zero means no retries.
+	MaxRetries int
+	// Pause between retries after the first one; zero value means default:
+	// period divided by 5, spreading default retries across one period.
+	RetryInterval time.Duration
+	// Skip samples with CRC mismatch and wait for the next one,
+	// instead of counting them as failed attempts. Number of samples
+	// skipped is available with GetSkippedBadCRC, to detect persistent
+	// corruption. Skips have their own limit equal to MaxRetries+1,
+	// so fetch doesn't wait forever on broken bus.
+	SkipBadCRC bool
+}
+
+// FetchUncompTemperatureAndHumidityWithOptions return uncompensated
+// temperature and humidity obtained from sensor, with retry behavior
+// defined by options; nil options mean default behavior (5 retries).
+// Backoff is deterministic: once read failed (sensor reply with NACK,
+// until data is ready), the first retry happens after full period,
+// when next measurement is surely ready, and each of the rest after
+// RetryInterval, so worst case latency is period + (MaxRetries-1)*RetryInterval.
+// Bus faults are not retried, and retry is not attempted,
+// if it would happen after context deadline.
+func (v *SHT3X) FetchUncompTemperatureAndHumidityWithOptions(parent context.Context,
+	i2c I2CBus, opts *FetchOptions) (ut uint16, uh uint16, err error) {
+
+	maxRetries := fetchRetryCount
+	period := v.getPeriodic()
+	retryInterval := period.getRetryDuration()
+	skipBadCRC := false
+	if opts != nil {
+		skipBadCRC = opts.SkipBadCRC
+		maxRetries = opts.MaxRetries
+		if maxRetries < 0 {
+			maxRetries = 0
+		}
+		if opts.RetryInterval > 0 {
+			retryInterval = opts.RetryInterval
+		}
+	}
+
+	if !v.periodicActive() {
 		return 0, 0, errors.New("Can't fetch measurement results, since no measurement initiated")
 	}
 	_, err = i2c.WriteBytes(CMD_PERIOD_FETCH)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, wrapBusError(i2c, CMD_PERIOD_FETCH, err)
 	}
 
 	// Create context with cancellation possibility.
@@ -608,20 +1403,53 @@ func (v *SHT3X) FetchUncompTemperatureAndHumidityWithContext(parent context.Cont
 	// run goroutine waiting for OS termination events, including keyboard Ctrl+C.
 	shell.CloseContextOnSignals(cancel, done, signals...)
 
-	retryCount := 5
+	retryCount := maxRetries
+	skipCount := maxRetries + 1
 	var data []uint16
-	timeDur := v.lastPeriodic.GetWaitDuration()
-	first := true
 	for retryCount >= 0 {
 		data, err = v.readDataWithCRCCheck(i2c, 2)
+		if skipBadCRC && skipCount > 0 && errors.Is(err, ErrCRCMismatch) {
+			skipCount--
+			v.mu.Lock()
+			v.crcSkipped++
+			v.mu.Unlock()
+			lg.Warnf("Sample with bad CRC skipped, wait for next one: %v", err)
+			// sample is consumed, so next one is ready in full period
+			select {
+			// check for termination request
+			case <-ctx.Done():
+				return 0, 0, ctx.Err()
+			case <-time.After(period.GetWaitDuration()):
+			}
+			continue
+		}
+		err = wrapBusError(i2c, CMD_PERIOD_FETCH, err)
 		// Once sensor doesn't ready provide data, sensor is replying with i2c NACK
 		// and it throw error "read /dev/i2c-x: no such device or address".
 		// So, we are retrying after pause specific to period parameter
 		// which define "measures per second" value.
 		if err != nil {
+			attempt := maxRetries - retryCount + 1
+			timeDur := period.getFetchRetryPause(attempt, retryInterval)
 			if retryCount == 0 {
+				lg.Debugf("Fetch attempt %d of %d failed, giving up: %v",
+					attempt, maxRetries+1, err)
+				return 0, 0, err
+			}
+			// bus fault is likely persistent, so fail fast
+			if isBusFault(err) {
+				lg.Debugf("Fetch attempt %d of %d failed with bus fault, don't retry: %v",
+					attempt, maxRetries+1, err)
 				return 0, 0, err
 			}
+			// don't wait, if next attempt would happen after deadline
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(timeDur).After(deadline) {
+				lg.Debugf("Fetch attempt %d of %d failed, no time left to retry: %v",
+					attempt, maxRetries+1, err)
+				return 0, 0, context.DeadlineExceeded
+			}
+			lg.Debugf("Fetch attempt %d of %d failed, retry in %v: %v",
+				attempt, maxRetries+1, timeDur, err)
 			// sleep timeDur time
 			select {
 			// check for termination request
@@ -631,23 +1459,99 @@ func (v *SHT3X) FetchUncompTemperatureAndHumidityWithContext(parent context.Cont
 			// sleep before new attempt.
 			case <-time.After(timeDur):
 			}
-			if first {
-				timeDur = timeDur / 10
-			}
 			retryCount--
 		} else {
 			break
 		}
-		first = false
+	}
+	err = v.checkStuck(data[0], data[1])
+	if err != nil {
+		return 0, 0, err
 	}
 	return data[0], data[1], nil
 }
 
+// GetSkippedBadCRC return number of samples with CRC mismatch
+// skipped by fetch with SkipBadCRC option enabled.
+func (v *SHT3X) GetSkippedBadCRC() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.crcSkipped
+}
+
+// Human readable names of commands, used to give context to bus errors.
+var commandNames = []struct {
+	cmd  []byte
+	name string
+}{
+	{CMD_SINGLE_MEASURE_HIGH_CSE, "single shot measure"},
+	{CMD_SINGLE_MEASURE_MEDIUM_CSE, "single shot measure"},
+	{CMD_SINGLE_MEASURE_LOW_CSE, "single shot measure"},
+	{CMD_SINGLE_MEASURE_HIGH, "single shot measure"},
+	{CMD_SINGLE_MEASURE_MEDIUM, "single shot measure"},
+	{CMD_SINGLE_MEASURE_LOW, "single shot measure"},
+	{CMD_ALERT_READ_HIGH_SET, "alert high set read"},
+	{CMD_ALERT_READ_HIGH_CLEAR, "alert high clear read"},
+	{CMD_ALERT_READ_LOW_CLEAR, "alert low clear read"},
+	{CMD_ALERT_READ_LOW_SET, "alert low set read"},
+	{CMD_ALERT_WRITE_HIGH_SET, "alert high set write"},
+	{CMD_ALERT_WRITE_HIGH_CLEAR, "alert high clear write"},
+	{CMD_ALERT_WRITE_LOW_CLEAR, "alert low clear write"},
+	{CMD_ALERT_WRITE_LOW_SET, "alert low set write"},
+	{CMD_ENABLE_HEATER, "heater enable"},
+	{CMD_DISABLE_HEATER, "heater disable"},
+	{CMD_READ_STATUS_REG, "status read"},
+	{CMD_CLEAR_STATUS_REG, "status clear"},
+	{CMD_PERIOD_FETCH, "periodic fetch"},
+	{CMD_ART, "ART"},
+	{CMD_BREAK, "break"},
+	{CMD_RESET, "reset"},
+	{CMD_READ_SERIAL, "serial number read"},
+	{CMD_GENERAL_CALL_RESET, "general call reset"},
+}
+
+// commandName return human readable name of command.
+func commandName(cmd []byte) string {
+	for _, item := range commandNames {
+		if bytes.Equal(item.cmd, cmd) {
+			return item.name
+		}
+	}
+	// all remaining commands start periodic measure
+	if len(cmd) == 2 && cmd[0] >= 0x20 && cmd[0] <= 0x27 {
+		return "periodic measure start"
+	}
+	return spew.Sprintf("command 0x%X", cmd)
+}
+
+// wrapBusError add command name, bus number and sensor address to error,
+// so it's clear from log which operation failed on which sensor.
+// Original error is kept in chain and available via errors.Is/As.
+func wrapBusError(i2c I2CBus, cmd []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s on bus %d addr 0x%02x: %w",
+		commandName(cmd), i2c.GetBus(), i2c.GetAddr(), err)
+}
+
+// isBusFault return true for i2c errors, which signal bus or driver failure
+// (I/O error, device removed, file closed), rather than sensor not being ready
+// yet, which is reported with NACK as ENXIO ("no such device or address").
+// Retrying in this case only wastes time.
+func isBusFault(err error) bool {
+	return errors.Is(err, syscall.EIO) || errors.Is(err, syscall.ENODEV) ||
+		errors.Is(err, syscall.EBADF)
+}
+
 // FetchTemperatureAndRelativeHumidity wait for uncompensated temperature
 // and humidity values and convert them to float values (Celsius and related humidity).
-func (v *SHT3X) FetchTemperatureAndRelativeHumidity(i2c *i2c.I2C) (temp float32, hum float32, err error) {
-	// Create default context
-	ctx := context.Background()
+// Call is limited by default timeout, which is 12 periods
+// of "periodic data acquisition mode" (24 sec for 0.5 MPS).
+func (v *SHT3X) FetchTemperatureAndRelativeHumidity(i2c I2CBus) (temp float32, hum float32, err error) {
+	// Create default context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), v.defaultFetchTimeout())
+	defer cancel()
 	// Reroute call
 	return v.FetchTemperatureAndRelativeHumidityWithContext(ctx, i2c)
 }
@@ -657,7 +1561,7 @@ func (v *SHT3X) FetchTemperatureAndRelativeHumidity(i2c *i2c.I2C) (temp float32,
 // Use context parameter, since operation is time consuming
 // (can take up to 2 seconds, waiting for results).
 func (v *SHT3X) FetchTemperatureAndRelativeHumidityWithContext(parent context.Context,
-	i2c *i2c.I2C) (temp float32, hum float32, err error) {
+	i2c I2CBus) (temp float32, hum float32, err error) {
 
 	ut, urh, err := v.FetchUncompTemperatureAndHumidityWithContext(parent, i2c)
 	if err != nil {
@@ -669,32 +1573,69 @@ func (v *SHT3X) FetchTemperatureAndRelativeHumidityWithContext(parent context.Co
 	return temp, hum, nil
 }
 
-// Read alert temperature and humidity limits from sensor.
-func (v *SHT3X) readAlertData(i2c *i2c.I2C, cmd []byte) (float32, float32, error) {
+// Read alert limits word from sensor as is.
+func (v *SHT3X) readAlertRaw(i2c I2CBus, cmd []byte) (uint16, error) {
 	_, err := i2c.WriteBytes(cmd)
 	if err != nil {
-		return 0, 0, err
+		return 0, wrapBusError(i2c, cmd, err)
 	}
-	v.lastCmd = cmd
+	v.setLastCmd(cmd)
 	data, err := v.readDataWithCRCCheck(i2c, 1)
+	if err != nil {
+		return 0, wrapBusError(i2c, cmd, err)
+	}
+	return data[0], nil
+}
+
+// Read alert temperature and humidity limits from sensor.
+// If calibrated is false, calibration offsets are not applied.
+func (v *SHT3X) readAlertData(i2c I2CBus, cmd []byte, calibrated bool) (float32, float32, error) {
+	u, err := v.readAlertRaw(i2c, cmd)
 	if err != nil {
 		return 0, 0, err
 	}
+	temp, rh := v.decodeAlertLimit(u, calibrated)
+	return temp, rh, nil
+}
 
-	uh := data[0] & 0xFE00
-	ut := data[0] & 0x01FF << 7
+// Decode alert limit word stored by sensor to temperature and humidity.
+// If calibrated is false, calibration offsets are not applied.
+func (v *SHT3X) decodeAlertLimit(u uint16, calibrated bool) (float32, float32) {
+	uh := u & 0xFE00
+	ut := u & 0x01FF << 7
 
+	if !calibrated {
+		return v.uncompToUncalibrated(ut, uh)
+	}
 	temp := v.uncompTemperatureToCelsius(ut)
 	rh := v.uncompHumidityToRelativeHumidity(uh)
-	return temp, rh, nil
+	return temp, rh
 }
 
+// ErrValueOutOfRange returned, when alert limit can't be represented by sensor:
+// temperature must be in range [-45..130]*C, humidity in range [0..100]%
+// once calibration offsets are subtracted.
+var ErrValueOutOfRange = errors.New("Value is out of range representable by sensor")
+
 // Write alert temperature and humidity limits to the sensor.
-func (v *SHT3X) writeAlertData(i2c *i2c.I2C, cmd []byte, temp, hum float32) error {
-	ut := v.celsiusToUncompTemperature(temp)
-	uh := v.relativeHumidityToUncompHimidity(hum)
+// Limits are given in calibrated values, so offsets defined
+// by SetCalibration are subtracted, before they are written.
+func (v *SHT3X) writeAlertData(i2c I2CBus, cmd []byte, temp, hum float32) error {
+	tempOffset, rhOffset := v.GetCalibration()
+	// Reroute call
+	return v.writeAlertDataUncalibrated(i2c, cmd, temp-tempOffset, hum-rhOffset)
+}
 
-	u := uh&0xFE00 | (ut & 0xFF80 >> 7)
+// Write alert temperature and humidity limits to the sensor as is,
+// in values sensor compare measurements with (calibration not applied).
+func (v *SHT3X) writeAlertDataUncalibrated(i2c I2CBus, cmd []byte, temp, hum float32) error {
+	// Reject values which would be silently clamped
+	if temp < alertTemperatureMin || temp > alertTemperatureMax ||
+		hum < alertHumidityMin || hum > alertHumidityMax {
+		lg.Debugf("Alert limit %v*C, %v%% (uncalibrated) is out of range", temp, hum)
+		return ErrValueOutOfRange
+	}
+	u := v.encodeAlertLimit(temp, hum)
 	data := []byte{byte(u & 0xFF00 >> 8), byte(u & 0x00FF)}
 	crc := calcCRC_SHT3X(0xFF, data)
 	b := append(cmd, data...)
@@ -702,9 +1643,9 @@ func (v *SHT3X) writeAlertData(i2c *i2c.I2C, cmd []byte, temp, hum float32) erro
 
 	_, err := i2c.WriteBytes(b)
 	if err != nil {
-		return err
+		return wrapBusError(i2c, cmd, err)
 	}
-	v.lastCmd = cmd
+	v.setLastCmd(cmd)
 	// No conversion time defined in docs for this command,
 	// but error thrown out, if no any pause provided.
 	time.Sleep(time.Millisecond * 1)
@@ -714,9 +1655,9 @@ func (v *SHT3X) writeAlertData(i2c *i2c.I2C, cmd []byte, temp, hum float32) erro
 
 // ReadAlertHighSet read sensor alert HIGH SET limits
 // for temperature and humidity.
-func (v *SHT3X) ReadAlertHighSet(i2c *i2c.I2C) (float32, float32, error) {
+func (v *SHT3X) ReadAlertHighSet(i2c I2CBus) (float32, float32, error) {
 	lg.Debug("Getting alert HIGH SET limit...")
-	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_HIGH_SET)
+	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_HIGH_SET, true)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -726,9 +1667,9 @@ func (v *SHT3X) ReadAlertHighSet(i2c *i2c.I2C) (float32, float32, error) {
 
 // ReadAlertHighClear read sensor alert HIGH CLEAR limits
 // for temperature and humidity.
-func (v *SHT3X) ReadAlertHighClear(i2c *i2c.I2C) (float32, float32, error) {
+func (v *SHT3X) ReadAlertHighClear(i2c I2CBus) (float32, float32, error) {
 	lg.Debug("Getting alert HIGH CLEAR limit...")
-	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_HIGH_CLEAR)
+	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_HIGH_CLEAR, true)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -738,9 +1679,9 @@ func (v *SHT3X) ReadAlertHighClear(i2c *i2c.I2C) (float32, float32, error) {
 
 // ReadAlertLowClear read sensor alert LOW CLEAR limits
 // for temperature and humidity.
-func (v *SHT3X) ReadAlertLowClear(i2c *i2c.I2C) (float32, float32, error) {
+func (v *SHT3X) ReadAlertLowClear(i2c I2CBus) (float32, float32, error) {
 	lg.Debug("Getting alert LOW CLEAR limit...")
-	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_LOW_CLEAR)
+	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_LOW_CLEAR, true)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -750,9 +1691,9 @@ func (v *SHT3X) ReadAlertLowClear(i2c *i2c.I2C) (float32, float32, error) {
 
 // ReadAlertLowSet read sensor alert LOW SET limits
 // for temperature and humidity.
-func (v *SHT3X) ReadAlertLowSet(i2c *i2c.I2C) (float32, float32, error) {
+func (v *SHT3X) ReadAlertLowSet(i2c I2CBus) (float32, float32, error) {
 	lg.Debug("Getting alert LOW SET limit...")
-	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_LOW_SET)
+	temp, rh, err := v.readAlertData(i2c, CMD_ALERT_READ_LOW_SET, true)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -762,7 +1703,8 @@ func (v *SHT3X) ReadAlertLowSet(i2c *i2c.I2C) (float32, float32, error) {
 
 // WriteAlertHighSet write alert HIGH SET limits
 // for temperature and humidity to the sensor.
-func (v *SHT3X) WriteAlertHighSet(i2c *i2c.I2C, temp, hum float32) error {
+// Return ErrValueOutOfRange, if values can't be represented by sensor.
+func (v *SHT3X) WriteAlertHighSet(i2c I2CBus, temp, hum float32) error {
 	lg.Debug("Setting alert HIGH SET limit...")
 	err := v.writeAlertData(i2c, CMD_ALERT_WRITE_HIGH_SET, temp, hum)
 	if err != nil {
@@ -774,7 +1716,8 @@ func (v *SHT3X) WriteAlertHighSet(i2c *i2c.I2C, temp, hum float32) error {
 
 // WriteAlertHighClear write alert HIGH CLEAR limits
 // for temperature and humidity to the sensor.
-func (v *SHT3X) WriteAlertHighClear(i2c *i2c.I2C, temp, hum float32) error {
+// Return ErrValueOutOfRange, if values can't be represented by sensor.
+func (v *SHT3X) WriteAlertHighClear(i2c I2CBus, temp, hum float32) error {
 	lg.Debug("Setting alert HIGH CLEAR limit...")
 	err := v.writeAlertData(i2c, CMD_ALERT_WRITE_HIGH_CLEAR, temp, hum)
 	if err != nil {
@@ -786,7 +1729,8 @@ func (v *SHT3X) WriteAlertHighClear(i2c *i2c.I2C, temp, hum float32) error {
 
 // WriteAlertLowClear write alert LOW CLEAR limits
 // for temperature and humidity to the sensor.
-func (v *SHT3X) WriteAlertLowClear(i2c *i2c.I2C, temp, hum float32) error {
+// Return ErrValueOutOfRange, if values can't be represented by sensor.
+func (v *SHT3X) WriteAlertLowClear(i2c I2CBus, temp, hum float32) error {
 	lg.Debug("Setting alert LOW CLEAR limit...")
 	err := v.writeAlertData(i2c, CMD_ALERT_WRITE_LOW_CLEAR, temp, hum)
 	if err != nil {
@@ -798,7 +1742,8 @@ func (v *SHT3X) WriteAlertLowClear(i2c *i2c.I2C, temp, hum float32) error {
 
 // WriteAlertLowSet write alert LOW SET limits
 // for temperature and humidity to the sensor.
-func (v *SHT3X) WriteAlertLowSet(i2c *i2c.I2C, temp, hum float32) error {
+// Return ErrValueOutOfRange, if values can't be represented by sensor.
+func (v *SHT3X) WriteAlertLowSet(i2c I2CBus, temp, hum float32) error {
 	lg.Debug("Setting alert LOW SET limit...")
 	err := v.writeAlertData(i2c, CMD_ALERT_WRITE_LOW_SET, temp, hum)
 	if err != nil {
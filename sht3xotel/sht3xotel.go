@@ -0,0 +1,74 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+// Package sht3xotel export SHT3x sensor measurements
+// as OpenTelemetry metrics.
+package sht3xotel
+
+import (
+	"context"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Instrument names.
+const (
+	TemperatureMetric = "sht3x.temperature"
+	HumidityMetric    = "sht3x.humidity"
+)
+
+// Register create observable gauges "sht3x.temperature" (unit "Cel")
+// and "sht3x.humidity" (unit "%") in the meter, and register callback,
+// which read sensor in "single shot mode" with precision specified each
+// time metrics are collected. Observations are tagged with i2c bus
+// and address attributes to distinguish multiple sensors.
+// Call Unregister on returned registration to stop collection.
+func Register(meter metric.Meter, sensor *sht3x.SHT3X, bus sht3x.I2CBus,
+	precision sht3x.MeasureRepeatability) (metric.Registration, error) {
+
+	temp, err := meter.Float64ObservableGauge(TemperatureMetric,
+		metric.WithUnit("Cel"),
+		metric.WithDescription("Temperature measured by SHT3x sensor"))
+	if err != nil {
+		return nil, err
+	}
+	hum, err := meter.Float64ObservableGauge(HumidityMetric,
+		metric.WithUnit("%"),
+		metric.WithDescription("Relative humidity measured by SHT3x sensor"))
+	if err != nil {
+		return nil, err
+	}
+	attrs := metric.WithAttributes(
+		attribute.Int("i2c.bus", bus.GetBus()),
+		attribute.Int("i2c.address", int(bus.GetAddr())),
+	)
+	return meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		t, rh, err := sensor.ReadTemperatureAndRelativeHumidity(bus, precision)
+		if err != nil {
+			return err
+		}
+		o.ObserveFloat64(temp, float64(t), attrs)
+		o.ObserveFloat64(hum, float64(rh), attrs)
+		return nil
+	}, temp, hum)
+}
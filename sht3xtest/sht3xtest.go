@@ -0,0 +1,124 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+// Package sht3xtest provide fake i2c-bus implementing sht3x.I2CBus,
+// to unit test code based on sht3x package without hardware.
+package sht3xtest
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+
+	sht3x "github.com/d2r2/go-sht3x"
+)
+
+// Verify Bus implement interface expected by sensor.
+var _ sht3x.I2CBus = (*Bus)(nil)
+
+// Bus is a fake i2c-bus connection to SHT3x sensor. Responses
+// are pre-loaded per command: once command is written, subsequent reads
+// return response bytes. Read with no response pending fail with ENXIO,
+// the same way real sensor reply with NACK, when data isn't ready.
+// Methods are safe for concurrent use, though fields should be accessed
+// once concurrent calls completed.
+type Bus struct {
+	BusNum    int
+	Addr      uint8
+	Writes    [][]byte // All data written to the bus, in order
+	WriteErr  error    // If not nil, returned by each write
+	ReadErr   error    // If not nil, returned by each read
+	responses map[string][]byte
+	pending   []byte
+	mu        sync.Mutex
+}
+
+// NewBus return new fake bus with bus number and sensor address specified.
+func NewBus(bus int, addr uint8) *Bus {
+	v := &Bus{BusNum: bus, Addr: addr, responses: make(map[string][]byte)}
+	return v
+}
+
+// key return map key of 2-byte command.
+func key(cmd []byte) string {
+	return fmt.Sprintf("%X", cmd)
+}
+
+// SetResponse define bytes returned by reads after command is written.
+// Use Words to build response from 16-bit words with CRC.
+func (v *Bus) SetResponse(cmd []byte, data []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.responses[key(cmd)] = append([]byte(nil), data...)
+}
+
+// WriteBytes implement sht3x.I2CBus interface.
+func (v *Bus) WriteBytes(buf []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.Writes = append(v.Writes, append([]byte(nil), buf...))
+	if v.WriteErr != nil {
+		return 0, v.WriteErr
+	}
+	v.pending = nil
+	if len(buf) >= 2 {
+		if data, ok := v.responses[key(buf[:2])]; ok {
+			v.pending = append([]byte(nil), data...)
+		}
+	}
+	return len(buf), nil
+}
+
+// ReadBytes implement sht3x.I2CBus interface.
+func (v *Bus) ReadBytes(buf []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.ReadErr != nil {
+		return 0, v.ReadErr
+	}
+	if len(v.pending) < len(buf) {
+		return 0, syscall.ENXIO
+	}
+	n := copy(buf, v.pending)
+	v.pending = v.pending[n:]
+	return n, nil
+}
+
+// GetBus implement sht3x.I2CBus interface.
+func (v *Bus) GetBus() int {
+	return v.BusNum
+}
+
+// GetAddr implement sht3x.I2CBus interface.
+func (v *Bus) GetAddr() uint8 {
+	return v.Addr
+}
+
+// Words encode 16-bit words the way sensor does: each word in big-endian
+// order followed by CRC byte.
+func Words(words ...uint16) []byte {
+	buf := make([]byte, 0, len(words)*3)
+	for _, w := range words {
+		data := []byte{byte(w >> 8), byte(w)}
+		buf = append(buf, data[0], data[1], sht3x.CalcCRC(data))
+	}
+	return buf
+}
@@ -0,0 +1,137 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3xtest_test
+
+import (
+	"errors"
+	"testing"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestReadTemperatureAndRelativeHumidity(t *testing.T) {
+	tests := []struct {
+		rawT, rawRH uint16
+		temp, rh    float32
+	}{
+		{0x0000, 0x0000, -45, 0},
+		{0xFFFF, 0xFFFF, 130, 100},
+		{0x6666, 0x8000, 25, 50},
+		{0x5EB9, 0x4000, 19.75, 25},
+		{0x0555, 0x9D11, -41.36, 61.36},
+	}
+	for _, test := range tests {
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_HIGH, sht3xtest.Words(test.rawT, test.rawRH))
+		sensor := sht3x.NewSHT3X()
+		temp, rh, err := sensor.ReadTemperatureAndRelativeHumidity(bus, sht3x.RepeatabilityHigh)
+		if err != nil {
+			t.Errorf("raw 0x%04X, 0x%04X: %v", test.rawT, test.rawRH, err)
+			continue
+		}
+		if temp != test.temp || rh != test.rh {
+			t.Errorf("raw 0x%04X, 0x%04X: got %v*C, %v%%, want %v*C, %v%%",
+				test.rawT, test.rawRH, temp, rh, test.temp, test.rh)
+		}
+	}
+}
+
+func TestAlertLimitRoundTrip(t *testing.T) {
+	// Alert limits keep 9 bits of temperature and 7 bits of humidity,
+	// so value read back might differ from written one by single step.
+	const tempStep, rhStep = 175.0 / 512, 100.0 / 128
+	tests := []struct {
+		temp, rh float32
+	}{
+		{-45, 0},
+		{130, 100},
+		{60, 80},
+		{-10, 22.5},
+		{25.3, 50.7},
+	}
+	for _, test := range tests {
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		sensor := sht3x.NewSHT3X()
+		err := sensor.WriteAlertHighSet(bus, test.temp, test.rh)
+		if err != nil {
+			t.Errorf("%v*C, %v%%: %v", test.temp, test.rh, err)
+			continue
+		}
+		// command, data word and CRC
+		w := bus.Writes[0]
+		if len(w) != 5 || sht3x.CalcCRC(w[2:4]) != w[4] {
+			t.Errorf("%v*C, %v%%: malformed write %X", test.temp, test.rh, w)
+			continue
+		}
+		bus.SetResponse(sht3x.CMD_ALERT_READ_HIGH_SET, w[2:])
+		temp, rh, err := sensor.ReadAlertHighSet(bus)
+		if err != nil {
+			t.Errorf("%v*C, %v%%: %v", test.temp, test.rh, err)
+			continue
+		}
+		if abs(temp-test.temp) > tempStep || abs(rh-test.rh) > rhStep {
+			t.Errorf("wrote %v*C, %v%%, read back %v*C, %v%%",
+				test.temp, test.rh, temp, rh)
+		}
+	}
+}
+
+func TestCRCMismatch(t *testing.T) {
+	tests := []struct {
+		block int // Data block with corrupted CRC
+	}{
+		{0},
+		{1},
+	}
+	for _, test := range tests {
+		data := sht3xtest.Words(0x6666, 0x8000)
+		crcPos := test.block*3 + 2
+		data[crcPos] ^= 0xFF
+		bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+		bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_HIGH, data)
+		sensor := sht3x.NewSHT3X()
+		_, _, err := sensor.ReadTemperatureAndRelativeHumidity(bus, sht3x.RepeatabilityHigh)
+		if !errors.Is(err, sht3x.ErrCRCMismatch) {
+			t.Errorf("block %d: got error %v, want ErrCRCMismatch", test.block, err)
+			continue
+		}
+		var crcErr *sht3x.CRCError
+		if !errors.As(err, &crcErr) {
+			t.Errorf("block %d: error %v is not CRCError", test.block, err)
+			continue
+		}
+		expected := sht3x.CalcCRC(data[test.block*3 : crcPos])
+		if crcErr.Block != test.block || crcErr.Actual != data[crcPos] ||
+			crcErr.Expected != expected {
+			t.Errorf("got %+v, want block %d, actual 0x%02X, expected 0x%02X",
+				*crcErr, test.block, data[crcPos], expected)
+		}
+	}
+}
+
+func abs(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+// StatusRegister keep sensor status register decoded to named flags.
+type StatusRegister struct {
+	AlertPending     bool
+	HeaterEnabled    bool
+	HumidityAlert    bool
+	TemperatureAlert bool
+	ResetDetected    bool
+	CommandFailed    bool
+	WriteCRCFailed   bool
+}
+
+// newStatusRegister decode raw status register value.
+func newStatusRegister(reg uint16) StatusRegister {
+	flags := StatusRegFlag(reg)
+	v := StatusRegister{
+		AlertPending:     flags&ALERT_PENDING != 0,
+		HeaterEnabled:    flags&HEATER_ENABLED != 0,
+		HumidityAlert:    flags&HUMIDITY_ALERT != 0,
+		TemperatureAlert: flags&TEMPERATURE_ALERT != 0,
+		ResetDetected:    flags&RESET_DETECTED != 0,
+		CommandFailed:    flags&COMMAND_FAILED != 0,
+		WriteCRCFailed:   flags&WRITE_DATA_CRC_FAILED != 0,
+	}
+	return v
+}
+
+// ReadStatus read status register and decode it to StatusRegister
+// with all flags at once. Register is always read from sensor
+// (see ReadStatusRegFresh), so alert and heater flags are up to date.
+func (v *SHT3X) ReadStatus(i2c I2CBus) (StatusRegister, error) {
+	ur, err := v.ReadStatusRegFresh(i2c)
+	if err != nil {
+		return StatusRegister{}, err
+	}
+	return newStatusRegister(ur), nil
+}
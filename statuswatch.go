@@ -0,0 +1,124 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"time"
+)
+
+// StatusChangeEvent describe single status register flag change.
+type StatusChangeEvent struct {
+	Flag   StatusRegFlag
+	NowSet bool
+}
+
+// All status register flags in the order events are emitted.
+var statusRegFlags = []StatusRegFlag{ALERT_PENDING, HEATER_ENABLED,
+	HUMIDITY_ALERT, TEMPERATURE_ALERT, RESET_DETECTED, COMMAND_FAILED,
+	WRITE_DATA_CRC_FAILED}
+
+// WatchStatus poll status register with interval specified and emit event
+// for each flag changed since previous poll, which gives edge-triggered
+// handling of alerts and reset detection. First successful read only
+// establish initial state, so no events produced for flags set at start.
+// Read errors are logged and skipped. Channel is closed, once context
+// is cancelled.
+func (v *SHT3X) WatchStatus(ctx context.Context, i2c I2CBus,
+	interval time.Duration) <-chan StatusChangeEvent {
+
+	ch := make(chan StatusChangeEvent)
+	go func() {
+		defer close(ch)
+		var prev StatusRegFlag
+		first := true
+		for {
+			// force sensor read, rather than cached value
+			reg, err := v.ReadStatusRegFresh(i2c)
+			if err != nil {
+				lg.Error(err)
+			} else {
+				cur := StatusRegFlag(reg)
+				if !first {
+					for _, flag := range statusRegFlags {
+						if (cur^prev)&flag == 0 {
+							continue
+						}
+						event := StatusChangeEvent{Flag: flag, NowSet: cur&flag != 0}
+						select {
+						case <-ctx.Done():
+							return
+						case ch <- event:
+						}
+					}
+				}
+				prev = cur
+				first = false
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}()
+	return ch
+}
+
+// Status register flags, which trigger MonitorAlerts callback.
+const monitoredAlerts = ALERT_PENDING | TEMPERATURE_ALERT | HUMIDITY_ALERT
+
+// MonitorAlerts poll status register with interval specified and call
+// callback with decoded status register, whenever any of ALERT_PENDING,
+// TEMPERATURE_ALERT or HUMIDITY_ALERT flags turn from clear to set.
+// It's edge-triggered: callback is not called again, while condition
+// persist, until flag is cleared and set once more. Flags are considered
+// clear at start, so alert already pending is reported on first poll.
+// Read errors are logged and skipped. Call block until context
+// is cancelled and return context error, so run it in goroutine
+// to monitor in background. Callback is called from the same goroutine,
+// so slow callback delay next poll.
+func (v *SHT3X) MonitorAlerts(ctx context.Context, i2c I2CBus,
+	pollInterval time.Duration, callback func(StatusRegister)) error {
+
+	lg.Debug("Monitoring alerts...")
+	var prev StatusRegFlag
+	for {
+		// force sensor read, rather than cached value
+		reg, err := v.ReadStatusRegFresh(i2c)
+		if err != nil {
+			lg.Error(err)
+		} else {
+			cur := StatusRegFlag(reg)
+			// flags turned from clear to set
+			if cur&^prev&monitoredAlerts != 0 {
+				callback(newStatusRegister(reg))
+			}
+			prev = cur
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,235 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Measurements return iterator over measurements obtained
+// in "periodic data acquisition mode", which is compatible with
+// Go 1.23 range-over-func statement:
+//
+//	for m := range sensor.Measurements(ctx, i2c, sht3x.Periodic1MPS, sht3x.RepeatabilityHigh) {
+//		...
+//	}
+//
+// Iterator start periodic mode, yield each measurement as soon as it's ready,
+// and issue Break, once loop exits or context is cancelled.
+// Fetch errors are logged and stop iteration. If duplicates suppression
+// is enabled with SetSuppressDuplicates, raw values identical to previous
+// ones are not yielded.
+func (v *SHT3X) Measurements(ctx context.Context, i2c I2CBus,
+	period PeriodicMeasure, precision MeasureRepeatability) func(yield func(Measurement) bool) {
+
+	return func(yield func(Measurement) bool) {
+		err := v.StartPeriodicTemperatureAndHumidityMeasure(i2c, period, precision)
+		if err != nil {
+			lg.Error(err)
+			return
+		}
+		defer func() {
+			err := v.Break(i2c)
+			if err != nil {
+				lg.Error(err)
+			}
+		}()
+		var filter duplicateFilter
+		for {
+			ut, urh, err := v.FetchUncompTemperatureAndHumidityWithContext(ctx, i2c)
+			if err != nil {
+				if ctx.Err() == nil {
+					lg.Error(err)
+				}
+				return
+			}
+			if !v.suppressDuplicate(&filter, ut, urh) {
+				if !yield(v.newMeasurement(ut, urh)) {
+					return
+				}
+			}
+			// wait until next measurement is ready
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(period.GetWaitDuration()):
+			}
+		}
+	}
+}
+
+// StreamMeasurements start "periodic data acquisition mode" and deliver
+// measurements to the channel at the pace defined by period, until context
+// is cancelled: then Break is issued and both channels are closed.
+// Fetch errors are delivered to error channel and stream continue,
+// except bus faults and failure to start, which are fatal: they are delivered
+// the same way and stream terminate. Error channel is buffered, so consumer
+// interested in measurements only doesn't block the stream: errors,
+// which don't fit the buffer, are logged and dropped. If duplicates
+// suppression is enabled with SetSuppressDuplicates, raw values identical
+// to previous ones are not delivered.
+func (v *SHT3X) StreamMeasurements(ctx context.Context, i2c I2CBus,
+	period PeriodicMeasure, precision MeasureRepeatability) (<-chan Measurement, <-chan error) {
+
+	ch := make(chan Measurement)
+	errs := make(chan error, 1)
+	sendErr := func(err error) {
+		select {
+		case errs <- err:
+		default:
+			lg.Error(err)
+		}
+	}
+	go func() {
+		defer close(errs)
+		defer close(ch)
+		err := v.StartPeriodicTemperatureAndHumidityMeasure(i2c, period, precision)
+		if err != nil {
+			sendErr(err)
+			return
+		}
+		defer func() {
+			err := v.Break(i2c)
+			if err != nil {
+				sendErr(err)
+			}
+		}()
+		var filter duplicateFilter
+		for {
+			ut, urh, err := v.FetchUncompTemperatureAndHumidityWithContext(ctx, i2c)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				sendErr(err)
+				if isBusFault(err) {
+					return
+				}
+			} else if !v.suppressDuplicate(&filter, ut, urh) {
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- v.newMeasurement(ut, urh):
+				}
+			}
+			// wait until next measurement is ready
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(period.GetWaitDuration()):
+			}
+		}
+	}()
+	return ch, errs
+}
+
+// SetSuppressDuplicates enable or disable suppression of measurements
+// with raw values identical to previous ones in Measurements iterator
+// and StreamMeasurements.
+// Duplicates are inevitable, when data fetched faster than sensor produce it.
+func (v *SHT3X) SetSuppressDuplicates(suppress bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.suppressDuplicates = suppress
+}
+
+// getSuppressDuplicates return true, if duplicates suppression is enabled.
+func (v *SHT3X) getSuppressDuplicates() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.suppressDuplicates
+}
+
+// GetSuppressedDuplicates return number of measurements suppressed
+// as duplicates by Measurements iterator and StreamMeasurements.
+func (v *SHT3X) GetSuppressedDuplicates() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.duplicates
+}
+
+// duplicateFilter keep raw values of previous measurement in stream.
+type duplicateFilter struct {
+	prevUT, prevURH uint16
+	valid           bool
+}
+
+// suppressDuplicate return true and count measurement as duplicate,
+// if suppression is enabled and raw values are identical to previous ones.
+func (v *SHT3X) suppressDuplicate(filter *duplicateFilter, ut, urh uint16) bool {
+	duplicate := filter.valid && ut == filter.prevUT && urh == filter.prevURH
+	filter.prevUT, filter.prevURH, filter.valid = ut, urh, true
+	if !duplicate || !v.getSuppressDuplicates() {
+		return false
+	}
+	lg.Debugf("Duplicate measurement suppressed: %v, %v", ut, urh)
+	v.mu.Lock()
+	v.duplicates++
+	v.mu.Unlock()
+	return true
+}
+
+// ScheduleReads make measurements in "single shot mode" aligned to wall clock
+// interval boundaries (for instance, with 10 sec interval at hh:mm:00, hh:mm:10,
+// hh:mm:20 and so on), so independent loggers produce matching timestamps.
+// Boundaries are computed from absolute time on each step, so drift caused
+// by measurement duration and scheduling delays doesn't accumulate;
+// missed boundaries are skipped. Read errors are logged and skipped.
+// Channel is closed, once context is cancelled. Interval must be positive.
+// Schedule follow real time, since waiting between reads is real:
+// clock injected with SetClock affect measurement timestamps only.
+func (v *SHT3X) ScheduleReads(ctx context.Context, i2c I2CBus,
+	precision MeasureRepeatability, interval time.Duration) (<-chan Measurement, error) {
+
+	if interval <= 0 {
+		return nil, errors.New(spew.Sprintf("Interval must be positive, but %v is given",
+			interval))
+	}
+	ch := make(chan Measurement)
+	go func() {
+		defer close(ch)
+		for {
+			now := time.Now()
+			next := now.Truncate(interval).Add(interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(next.Sub(now)):
+			}
+			m, err := v.ReadMeasurement(i2c, precision)
+			if err != nil {
+				lg.Error(err)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- m:
+			}
+		}
+	}()
+	return ch, nil
+}
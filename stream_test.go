@@ -0,0 +1,103 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sht3x "github.com/d2r2/go-sht3x"
+	"github.com/d2r2/go-sht3x/sht3xtest"
+)
+
+func TestScheduleReadsInterval(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		ch, err := sensor.ScheduleReads(context.Background(), bus,
+			sht3x.RepeatabilityLow, interval)
+		if err == nil || ch != nil {
+			t.Errorf("interval %v accepted", interval)
+		}
+	}
+	if len(bus.Writes) != 0 {
+		t.Errorf("got bus writes %X, want none", bus.Writes)
+	}
+}
+
+func TestScheduleReadsFrozenClock(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_SINGLE_MEASURE_LOW, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	fixed := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor.SetClock(func() time.Time { return fixed })
+
+	const interval = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := sensor.ScheduleReads(ctx, bus, sht3x.RepeatabilityLow, interval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	const reads = 3
+	for i := 0; i < reads; i++ {
+		m := <-ch
+		if !m.Timestamp.Equal(fixed) {
+			t.Errorf("timestamp = %v, want %v", m.Timestamp, fixed)
+		}
+	}
+	// frozen clock must not collapse schedule into tight loop
+	if elapsed := time.Since(start); elapsed < (reads-1)*interval {
+		t.Errorf("%d reads took %v, want at least %v", reads, elapsed, (reads-1)*interval)
+	}
+}
+
+func TestStreamMeasurementsSuppressDuplicates(t *testing.T) {
+	bus := sht3xtest.NewBus(1, sht3x.AddressDefault)
+	bus.SetResponse(sht3x.CMD_PERIOD_FETCH, sht3xtest.Words(0x6666, 0x8000))
+	sensor := sht3x.NewSHT3X()
+	sensor.SetSuppressDuplicates(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, errs := sensor.StreamMeasurements(ctx, bus, sht3x.Periodic10MPS, sht3x.RepeatabilityLow)
+	if m := <-ch; m.Temperature != 25 || m.RelativeHumidity != 50 {
+		t.Errorf("got %v*C, %v%%, want 25*C, 50%%", m.Temperature, m.RelativeHumidity)
+	}
+	// sensor keep returning the same raw values, so nothing more is delivered
+	select {
+	case m := <-ch:
+		t.Errorf("duplicate measurement delivered: %+v", m)
+	case <-time.After(350 * time.Millisecond):
+	}
+	cancel()
+	for range ch {
+	}
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := sensor.GetSuppressedDuplicates(); n < 2 {
+		t.Errorf("%d duplicates suppressed, want at least 2", n)
+	}
+}
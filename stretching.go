@@ -0,0 +1,104 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"time"
+)
+
+// getSingleMeasurementClockStretchCommand return "single shot mode"
+// command with clock stretching enabled.
+func getSingleMeasurementClockStretchCommand(precision MeasureRepeatability) []byte {
+	var cmd []byte
+	switch precision {
+	case RepeatabilityLow:
+		cmd = CMD_SINGLE_MEASURE_LOW_CSE
+	case RepeatabilityMedium:
+		cmd = CMD_SINGLE_MEASURE_MEDIUM_CSE
+	case RepeatabilityHigh:
+		cmd = CMD_SINGLE_MEASURE_HIGH_CSE
+	}
+	return cmd
+}
+
+// ReadUncompTemperatureAndHumidityClockStretch returns uncompensated humidity
+// and temperature obtained from sensor in "single shot mode" with clock
+// stretching enabled: sensor hold the bus (SCL line low) until conversion
+// complete, so data is read right after command, without fixed pause.
+// This help on slow buses, where ordinary read might get NACK before
+// conversion finish. Bus master must support clock stretching
+// (see SupportsClockStretching), otherwise read fail or return garbage.
+func (v *SHT3X) ReadUncompTemperatureAndHumidityClockStretch(i2c I2CBus,
+	precision MeasureRepeatability) (uint16, uint16, error) {
+
+	lg.Debug("Measuring temperature and humidity with clock stretching...")
+	cmd := getSingleMeasurementClockStretchCommand(precision)
+	err := v.initiateMeasure(i2c, cmd, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	data, err := v.readDataWithCRCCheck(i2c, 2)
+	if err != nil {
+		return 0, 0, wrapBusError(i2c, cmd, err)
+	}
+	err = v.checkStuck(data[0], data[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return data[0], data[1], nil
+}
+
+// SupportsClockStretching detect whether i2c-bus master supports clock stretching,
+// to decide if measurement commands with clock stretching enabled could be used.
+// Method issue high repeatability measurement with clock stretching enabled
+// and read results immediately, then make ordinary measurement with pause
+// for comparison. Clock stretching considered supported, if first read
+// succeeded, took at least half of conversion time (so master was really
+// held until data was ready) and results are close to ordinary measurement.
+func (v *SHT3X) SupportsClockStretching(i2c I2CBus) (bool, error) {
+	lg.Debug("Detecting clock stretching support...")
+	const precision = RepeatabilityHigh
+	start := time.Now()
+	ut1, urh1, err := v.ReadUncompTemperatureAndHumidityClockStretch(i2c, precision)
+	elapsed := time.Since(start)
+	if err != nil {
+		if isBusFault(err) {
+			return false, err
+		}
+		lg.Debugf("Read with clock stretching failed: %v", err)
+		// Let sensor complete conversion before next command
+		time.Sleep(precision.GetMeasureTime())
+		return false, nil
+	}
+	lg.Debugf("Read with clock stretching took %v", elapsed)
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return false, err
+	}
+	temp1, rh1 := v.uncompTemperatureToCelsius(ut1), v.uncompHumidityToRelativeHumidity(urh1)
+	temp2, rh2 := v.uncompTemperatureToCelsius(ut), v.uncompHumidityToRelativeHumidity(urh)
+	const tempTol, rhTol = 1, 3
+	supported := elapsed >= precision.GetMeasureTime()/2 &&
+		abs32(temp1-temp2) <= tempTol && abs32(rh1-rh2) <= rhTol
+	return supported, nil
+}
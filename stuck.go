@@ -0,0 +1,64 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import "errors"
+
+// ErrSensorStuck returned, when sensor produce identical raw values
+// in too many consecutive reads, which signal faulty sensor, or latched
+// value. Such data pass CRC verification, so it can't be caught otherwise.
+var ErrSensorStuck = errors.New("Sensor is stuck, readings don't change")
+
+// SetStuckDetection enable detection of stuck sensor: once raw temperature
+// and humidity are byte-identical in limit consecutive reads (either
+// in "single shot mode", or "periodic data acquisition mode"),
+// read methods return ErrSensorStuck, until values change.
+// Real sensor noise make identical values rare, but choose limit
+// high enough (dozens of reads) for stable environment and low repeatability.
+// Zero value disable detection, which is default.
+func (v *SHT3X) SetStuckDetection(limit int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.stuckLimit = limit
+	v.stuckCount = 0
+}
+
+// checkStuck account raw values just read and return ErrSensorStuck,
+// if they remain identical for too long.
+func (v *SHT3X) checkStuck(ut, urh uint16) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.stuckLimit <= 0 {
+		return nil
+	}
+	if v.stuckCount > 0 && ut == v.stuckLastT && urh == v.stuckLastRH {
+		v.stuckCount++
+	} else {
+		v.stuckCount = 1
+		v.stuckLastT, v.stuckLastRH = ut, urh
+	}
+	if v.stuckCount >= v.stuckLimit {
+		lg.Warnf("Raw values %v, %v unchanged in %d reads", ut, urh, v.stuckCount)
+		return ErrSensorStuck
+	}
+	return nil
+}
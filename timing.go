@@ -0,0 +1,97 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import "time"
+
+// TimingStats accumulate time spent in measurement phases:
+// writing measurement commands to the bus, waiting for conversion,
+// and reading data from the bus. Helps to find out whether conversion
+// latency or bus transfers dominate.
+type TimingStats struct {
+	Writes     int
+	WriteTotal time.Duration
+	Waits      int
+	WaitTotal  time.Duration
+	Reads      int
+	ReadTotal  time.Duration
+}
+
+// addWrite register duration of measurement command write.
+// SHT3X calls it with mu locked.
+func (v *TimingStats) addWrite(dur time.Duration) {
+	v.Writes++
+	v.WriteTotal += dur
+}
+
+// addWait register duration of conversion wait.
+// SHT3X calls it with mu locked.
+func (v *TimingStats) addWait(dur time.Duration) {
+	v.Waits++
+	v.WaitTotal += dur
+}
+
+// addRead register duration of data read.
+// SHT3X calls it with mu locked.
+func (v *TimingStats) addRead(dur time.Duration) {
+	v.Reads++
+	v.ReadTotal += dur
+}
+
+// average return average duration or zero, if count is zero.
+func average(total time.Duration, count int) time.Duration {
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// WriteAverage return average duration of measurement command write.
+func (v TimingStats) WriteAverage() time.Duration {
+	return average(v.WriteTotal, v.Writes)
+}
+
+// WaitAverage return average duration of conversion wait.
+func (v TimingStats) WaitAverage() time.Duration {
+	return average(v.WaitTotal, v.Waits)
+}
+
+// ReadAverage return average duration of data read.
+func (v TimingStats) ReadAverage() time.Duration {
+	return average(v.ReadTotal, v.Reads)
+}
+
+// TimingStats return time accumulated in measurement phases:
+// measurement commands write, conversion wait and all CRC-protected
+// data reads (including status register and alert limits).
+func (v *SHT3X) TimingStats() TimingStats {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.timing
+}
+
+// ResetTimingStats clear accumulated timing statistics.
+func (v *SHT3X) ResetTimingStats() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.timing = TimingStats{}
+}
@@ -0,0 +1,171 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import (
+	"github.com/davecgh/go-spew/spew"
+)
+
+// Celsius is a strongly typed temperature value in Celsius degrees.
+type Celsius float32
+
+// Fahrenheit convert temperature to Fahrenheit degrees.
+func (v Celsius) Fahrenheit() float32 {
+	return round32(float32(v)*9/5+32, 2)
+}
+
+// Kelvin convert temperature to Kelvin degrees.
+func (v Celsius) Kelvin() float32 {
+	return round32(float32(v)+273.15, 2)
+}
+
+// String define stringer interface.
+func (v Celsius) String() string {
+	return spew.Sprintf("%v*C", float32(v))
+}
+
+// RelativeHumidity is a strongly typed relative humidity value in percent.
+type RelativeHumidity float32
+
+// Ratio convert relative humidity from percent to ratio in range [0..1].
+func (v RelativeHumidity) Ratio() float32 {
+	return float32(v) / 100
+}
+
+// String define stringer interface.
+func (v RelativeHumidity) String() string {
+	return spew.Sprintf("%v%%", float32(v))
+}
+
+// TemperatureUnit define unit to report temperature in.
+type TemperatureUnit int
+
+const (
+	UnitCelsius    TemperatureUnit = iota + 1 // Celsius degrees
+	UnitFahrenheit                            // Fahrenheit degrees
+	UnitKelvin                                // Kelvin degrees
+)
+
+// String define stringer interface.
+func (v TemperatureUnit) String() string {
+	switch v {
+	case UnitCelsius:
+		return "Celsius"
+	case UnitFahrenheit:
+		return "Fahrenheit"
+	case UnitKelvin:
+		return "Kelvin"
+	default:
+		return "<unknown>"
+	}
+}
+
+// fromCelsius convert temperature from Celsius to unit,
+// rounded to two decimals.
+func (v TemperatureUnit) fromCelsius(tempC float32) float32 {
+	switch v {
+	case UnitFahrenheit:
+		return Celsius(tempC).Fahrenheit()
+	case UnitKelvin:
+		return Celsius(tempC).Kelvin()
+	default:
+		return round32(tempC, 2)
+	}
+}
+
+// toCelsius convert temperature from unit to Celsius,
+// rounded to two decimals. It's reverse of fromCelsius.
+func (v TemperatureUnit) toCelsius(temp float32) float32 {
+	switch v {
+	case UnitFahrenheit:
+		return round32((temp-32)*5/9, 2)
+	case UnitKelvin:
+		return round32(temp-273.15, 2)
+	default:
+		return round32(temp, 2)
+	}
+}
+
+// ReadTemperatureInUnit returns temperature in unit specified
+// and relative humidity obtained from sensor in "single shot mode".
+func (v *SHT3X) ReadTemperatureInUnit(i2c I2CBus, precision MeasureRepeatability,
+	unit TemperatureUnit) (float32, float32, error) {
+
+	temp, rh, err := v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, err
+	}
+	return unit.fromCelsius(temp), rh, nil
+}
+
+// ReadCelsiusAndRelativeHumidity returns strongly typed temperature
+// and relative humidity obtained from sensor in "single shot mode".
+func (v *SHT3X) ReadCelsiusAndRelativeHumidity(i2c I2CBus,
+	precision MeasureRepeatability) (Celsius, RelativeHumidity, error) {
+
+	temp, rh, err := v.ReadTemperatureAndRelativeHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, err
+	}
+	return Celsius(temp), RelativeHumidity(rh), nil
+}
+
+// FetchCelsiusAndRelativeHumidity returns strongly typed temperature
+// and relative humidity obtained from sensor in "periodic data acquisition mode".
+func (v *SHT3X) FetchCelsiusAndRelativeHumidity(i2c I2CBus) (Celsius, RelativeHumidity, error) {
+	temp, rh, err := v.FetchTemperatureAndRelativeHumidity(i2c)
+	if err != nil {
+		return 0, 0, err
+	}
+	return Celsius(temp), RelativeHumidity(rh), nil
+}
+
+// ReadTemperatureFahrenheitAndRelativeHumidity returns temperature
+// in Fahrenheit and relative humidity obtained from sensor
+// in "single shot mode".
+func (v *SHT3X) ReadTemperatureFahrenheitAndRelativeHumidity(i2c I2CBus,
+	precision MeasureRepeatability) (float32, float32, error) {
+
+	ut, urh, err := v.ReadUncompTemperatureAndHumidity(i2c, precision)
+	if err != nil {
+		return 0, 0, err
+	}
+	lg.Debugf("Temperature and RH uncompensated = %v, %v", ut, urh)
+	temp := UnitFahrenheit.fromCelsius(v.uncompTemperatureToCelsius(ut))
+	rh := v.uncompHumidityToRelativeHumidity(urh)
+	return temp, rh, nil
+}
+
+// FetchTemperatureFahrenheitAndRelativeHumidity returns temperature
+// in Fahrenheit and relative humidity obtained from sensor
+// in "periodic data acquisition mode".
+// Call is limited by default timeout, the same as for FetchTemperatureAndRelativeHumidity.
+func (v *SHT3X) FetchTemperatureFahrenheitAndRelativeHumidity(i2c I2CBus) (float32, float32, error) {
+	ut, urh, err := v.FetchUncompTemperatureAndHumidity(i2c)
+	if err != nil {
+		return 0, 0, err
+	}
+	lg.Debugf("Temperature and RH uncompensated = %v, %v", ut, urh)
+	temp := UnitFahrenheit.fromCelsius(v.uncompTemperatureToCelsius(ut))
+	rh := v.uncompHumidityToRelativeHumidity(urh)
+	return temp, rh, nil
+}
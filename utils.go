@@ -25,8 +25,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math"
-
-	i2c "github.com/d2r2/go-i2c"
 )
 
 // Utility functions
@@ -94,6 +92,15 @@ func calcCRC_SHT3X(seed byte, buf []byte) byte {
 	return seed
 }
 
+// CalcCRC return CRC-8 of data calculated the same way as sensor does:
+// polynomial 0x31 (x^8 + x^5 + x^4 + 1), initialization 0xFF,
+// no reflection, no final XOR. For instance, CRC of 0xBEEF is 0x92.
+// Use it to verify data received, or build command with data
+// (like alert limit) yourself.
+func CalcCRC(data []byte) byte {
+	return calcCRC_SHT3X(0xFF, data)
+}
+
 // Round float amount to certain procision.
 func round64(value float64, precision int) float64 {
 	value2 := math.Round(value*math.Pow10(precision)) /
@@ -101,13 +108,31 @@ func round64(value float64, precision int) float64 {
 	return value2
 }
 
+// Convert float amount to uint16 limiting it to [0..0xFFFF] range.
+func clampU16(value float64) uint16 {
+	if value < 0 {
+		return 0
+	} else if value > 0xFFFF {
+		return 0xFFFF
+	}
+	return uint16(value)
+}
+
+// Return absolute value of float amount.
+func abs32(value float32) float32 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
 // Round float amount to certain procision.
 func round32(value float32, precision int) float32 {
 	return float32(round64(float64(value), precision))
 }
 
 // Read byte block from i2c device to struct object.
-func readDataToStruct(i2c *i2c.I2C, byteCount int,
+func readDataToStruct(i2c I2CBus, byteCount int,
 	byteOrder binary.ByteOrder, obj interface{}) error {
 	buf1 := make([]byte, byteCount)
 	_, err := i2c.ReadBytes(buf1)
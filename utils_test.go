@@ -0,0 +1,44 @@
+//--------------------------------------------------------------------------------------------------
+//
+// Copyright (c) 2018 Denis Dyakov
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and
+// associated documentation files (the "Software"), to deal in the Software without restriction,
+// including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so,
+// subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial
+// portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING
+// BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+//
+//--------------------------------------------------------------------------------------------------
+
+package sht3x
+
+import "testing"
+
+func TestCalcCRC(t *testing.T) {
+	tests := []struct {
+		data []byte
+		crc  byte
+	}{
+		{[]byte{0xBE, 0xEF}, 0x92}, // datasheet example
+		{[]byte{0x00, 0x00}, 0x81},
+		{[]byte{0xFF, 0xFF}, 0xAC},
+		{[]byte{0x66, 0x66}, 0x93},
+		{[]byte{0x80, 0x00}, 0xA2},
+		{[]byte{0x01}, 0x9D},
+		{[]byte{}, 0xFF}, // initialization value
+	}
+	for _, test := range tests {
+		if crc := CalcCRC(test.data); crc != test.crc {
+			t.Errorf("CalcCRC(%X) = 0x%02X, want 0x%02X", test.data, crc, test.crc)
+		}
+	}
+}